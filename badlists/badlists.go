@@ -0,0 +1,341 @@
+// Package badlists loads bad package lists and matches dependencies against
+// them.
+package badlists
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joelcma/dewormer/readers"
+	"github.com/joelcma/dewormer/versions"
+)
+
+// Entry is a single bad package from a list.
+type Entry struct {
+	// Ecosystem is the purl type the entry applies to (see the readers
+	// Ecosystem constants). Legacy name@version entries have no ecosystem
+	// and match a package of that name in any ecosystem.
+	Ecosystem string
+	Name      string
+	// Version is an exact version, "*" or a version range in the syntax of
+	// the ecosystem (see versions.Parse). For OSV advisories it describes
+	// the affected versions.
+	Version string
+	// List is the file name of the list the entry came from, and ListPath
+	// the path it was loaded from.
+	List     string
+	ListPath string
+	// Advisory and Summary are the ID and summary of the OSV advisory the
+	// entry was read from, if any.
+	Advisory string
+	Summary  string
+	// Severity (low, moderate, high or critical), References, Reason and
+	// Added (the date the entry was added) come from structured lists and
+	// advisories; plain text lists have none.
+	Severity   string
+	References []string
+	Reason     string
+	Added      string
+
+	// constraint, when set, is used instead of parsing Version.
+	constraint versions.Constraint
+}
+
+// Set is an index of bad package entries.
+type Set struct {
+	entries map[string][]Entry
+	// legacy indexes legacy entries under their name as normalized in each
+	// ecosystem, since they apply to all of them.
+	legacy map[string][]Entry
+	count  int
+	// constraints caches parsed versions by ecosystem and version; legacy
+	// entries are parsed once for each ecosystem they are compared in.
+	constraints map[string]versions.Constraint
+}
+
+func NewSet() *Set {
+	return &Set{
+		entries:     make(map[string][]Entry),
+		legacy:      make(map[string][]Entry),
+		constraints: make(map[string]versions.Constraint),
+	}
+}
+
+func setKey(ecosystem, name string) string {
+	return ecosystem + "\x00" + readers.NormalizeName(ecosystem, name)
+}
+
+// Add adds an entry to the set.
+func (s *Set) Add(e Entry) {
+	key := setKey(e.Ecosystem, e.Name)
+	s.entries[key] = append(s.entries[key], e)
+	s.count++
+
+	if e.Ecosystem != "" {
+		return
+	}
+	for _, ecosystem := range ecosystems {
+		key := setKey(ecosystem, e.Name)
+		s.legacy[key] = append(s.legacy[key], e)
+	}
+}
+
+// Len returns the number of entries in the set.
+func (s *Set) Len() int {
+	return s.count
+}
+
+// Match returns the entries dep is listed by: entries for the dependency's
+// ecosystem and legacy entries without an ecosystem whose version or range
+// includes the dependency's version. Names and ranges are compared using the
+// rules of the dependency's ecosystem, so a legacy Requests_Toolbelt entry
+// matches the PyPI package requests-toolbelt.
+func (s *Set) Match(dep readers.Dependency) []Entry {
+	var candidates []Entry
+	switch {
+	case dep.Ecosystem == "":
+		candidates = s.entries[setKey("", dep.Name)]
+	case slices.Contains(ecosystems, dep.Ecosystem):
+		key := setKey(dep.Ecosystem, dep.Name)
+		candidates = append(slices.Clone(s.legacy[key]), s.entries[key]...)
+	default:
+		// other purl types compare names as written
+		candidates = append(slices.Clone(s.entries[setKey("", dep.Name)]), s.entries[setKey(dep.Ecosystem, dep.Name)]...)
+	}
+
+	var matches []Entry
+	for _, e := range candidates {
+		c := e.constraint
+		if c == nil {
+			c = s.constraint(dep.Ecosystem, e.Version)
+		}
+		if c.Matches(dep.Version) {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// MatchName returns the entries matching a package given without an
+// ecosystem: legacy entries and the entries of any ecosystem that list a
+// package of that name. Legacy entries are compared under the rules of every
+// ecosystem, as they would be when scanning, and returned once.
+func (s *Set) MatchName(name, version string) []Entry {
+	var matches []Entry
+	seen := make(map[string]bool)
+	for _, ecosystem := range append([]string{""}, ecosystems...) {
+		for _, e := range s.Match(readers.Dependency{Name: name, Version: version, Ecosystem: ecosystem}) {
+			if e.Ecosystem == "" {
+				key := e.List + "\x00" + e.Name + "\x00" + e.Version
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// ecosystems are the ecosystems MatchName looks in, in a stable order.
+var ecosystems = []string{
+	readers.EcosystemNpm,
+	readers.EcosystemMaven,
+	readers.EcosystemPyPI,
+	readers.EcosystemGo,
+	readers.EcosystemCargo,
+	readers.EcosystemGem,
+	readers.EcosystemComposer,
+	readers.EcosystemNuGet,
+}
+
+// constraint returns the parsed version or range. A legacy range that isn't
+// valid in the ecosystem it's compared in is logged once and matches nothing.
+func (s *Set) constraint(ecosystem, version string) versions.Constraint {
+	key := ecosystem + "\x00" + version
+	if c, ok := s.constraints[key]; ok {
+		return c
+	}
+
+	c, err := versions.Parse(ecosystem, version)
+	if err != nil {
+		log.Printf("Ignoring version %q for %s packages: %v", version, ecosystem, err)
+		c = versions.None{}
+	}
+	s.constraints[key] = c
+	return c
+}
+
+// severityRanks orders the severities used by lists and advisories.
+var severityRanks = map[string]int{
+	"low":      1,
+	"moderate": 2,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// SeverityRank orders severities from 1 (low) to 4 (critical). Unknown or
+// missing severities rank 0.
+func SeverityRank(severity string) int {
+	return severityRanks[strings.ToLower(severity)]
+}
+
+// ListError is a list that could not be loaded.
+type ListError struct {
+	Path string
+	Err  error
+}
+
+func (e ListError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Load reads the given lists into a new set. A list is a text file with one
+// entry per line, a structured list (.json, .yaml or .yml), an OSV advisory
+// (.json), or a directory or zip archive of OSV advisories. Lists that cannot
+// be read are logged, skipped and returned as errors; entries that cannot be
+// parsed are logged and skipped.
+func Load(listPaths []string) (*Set, []ListError) {
+	return LoadData(listPaths, nil)
+}
+
+// LoadData is Load for lists whose contents have already been read, such as
+// lists whose signature was checked, so that what is loaded is exactly what
+// was read. data maps list paths to their contents; other lists are read
+// from disk.
+func LoadData(listPaths []string, data map[string][]byte) (*Set, []ListError) {
+	set := NewSet()
+	var errs []ListError
+	for _, listPath := range listPaths {
+		if err := loadList(set, listPath, data[listPath]); err != nil {
+			log.Printf("Could not open bad package list %s: %v", listPath, err)
+			errs = append(errs, ListError{Path: listPath, Err: err})
+		}
+	}
+	return set, errs
+}
+
+// loadList loads one list, from data if it isn't nil.
+func loadList(set *Set, listPath string, data []byte) error {
+	if data == nil {
+		info, err := os.Stat(listPath)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return loadOSVDir(set, listPath)
+		}
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(listPath)); {
+	case ext == ".zip":
+		return loadOSVZip(set, listPath, data)
+	case ext == ".json":
+		return loadJSONFile(set, listPath, listPath, data)
+	case ext == ".yaml" || ext == ".yml":
+		if data == nil {
+			var err error
+			if data, err = os.ReadFile(listPath); err != nil {
+				return err
+			}
+		}
+		entries, err := parseStructured(data, true, filepath.Base(listPath))
+		if err != nil {
+			return err
+		}
+		addEntries(set, entries, listPath)
+		return nil
+	}
+	return loadTextFile(set, listPath, data)
+}
+
+func loadTextFile(set *Set, listPath string, data []byte) error {
+	var r io.Reader = bytes.NewReader(data)
+	if data == nil {
+		file, err := os.Open(listPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	listName := filepath.Base(listPath)
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip comments and empty lines
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entry, err := ParseLine(line)
+		if err != nil {
+			log.Printf("Skipping %s:%d: %v", listName, lineNo, err)
+			continue
+		}
+		entry.List, entry.ListPath = listName, listPath
+		set.Add(entry)
+	}
+	return scanner.Err()
+}
+
+// LatestModTime returns the most recent modification time of the given lists,
+// including every file inside list directories.
+func LatestModTime(listPaths []string) time.Time {
+	var latest time.Time
+	for _, listPath := range listPaths {
+		filepath.WalkDir(listPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() && path != listPath && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+				latest = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return latest
+}
+
+// ParseLine parses a list entry, either a Package URL
+// (pkg:npm/%40scope/name@1.2.3) or the legacy package@version format. The
+// version may be "*" or a range (pkg:npm/foo@>=1.0.0 <1.2.0).
+func ParseLine(line string) (Entry, error) {
+	if _, ok := cutPrefixFold(line, "pkg:"); ok {
+		ecosystem, name, version, err := ParsePurl(line)
+		if err != nil {
+			return Entry{}, err
+		}
+		if version == "" {
+			return Entry{}, fmt.Errorf("package URL %q has no version", line)
+		}
+		if _, err := versions.Parse(ecosystem, version); err != nil {
+			return Entry{}, err
+		}
+		return Entry{Ecosystem: ecosystem, Name: name, Version: version}, nil
+	}
+
+	// Expected format: package@version. Split on the last @ to handle
+	// scoped packages like @rxap/ngx-bootstrap
+	at := strings.LastIndex(line, "@")
+	if at <= 0 || at == len(line)-1 {
+		return Entry{}, fmt.Errorf("expected package@version, got %q", line)
+	}
+	return Entry{Name: line[:at], Version: line[at+1:]}, nil
+}
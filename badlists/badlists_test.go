@@ -0,0 +1,166 @@
+package badlists
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joelcma/dewormer/readers"
+)
+
+func TestLoad_LegacyAndPurlEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "mixed.txt")
+	content := `# comment
+voip-callkit@1.0.2
+@rxap/ngx-bootstrap@19.0.3
+pkg:npm/foo@1.0.0
+pkg:pypi/Requests_Toolbelt@1.0.0
+not-an-entry
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing list: %v", err)
+	}
+
+	set, errs := Load([]string{path, filepath.Join(tmpDir, "missing.txt")})
+	if set.Len() != 4 {
+		t.Fatalf("expected 4 entries, got %d", set.Len())
+	}
+	if len(errs) != 1 || errs[0].Path != filepath.Join(tmpDir, "missing.txt") {
+		t.Fatalf("expected the missing list as an error, got %v", errs)
+	}
+
+	matches := set.Match(readers.Dependency{Name: "@rxap/ngx-bootstrap", Version: "19.0.3", Ecosystem: readers.EcosystemNpm})
+	if len(matches) != 1 || matches[0].List != "mixed.txt" {
+		t.Fatalf("expected scoped legacy entry to match, got %+v", matches)
+	}
+	if m := set.Match(readers.Dependency{Name: "requests-toolbelt", Version: "1.0.0", Ecosystem: readers.EcosystemPyPI}); len(m) != 1 {
+		t.Fatalf("expected PEP 503 normalized purl entry to match, got %+v", m)
+	}
+}
+
+func TestMatch_SameEcosystemOnly(t *testing.T) {
+	set := NewSet()
+	set.Add(Entry{Ecosystem: readers.EcosystemNpm, Name: "foo", Version: "1.0.0", List: "npm.txt"})
+	set.Add(Entry{Name: "bar", Version: "2.0.0", List: "legacy.txt"})
+
+	if m := set.Match(readers.Dependency{Name: "foo", Version: "1.0.0", Ecosystem: readers.EcosystemNpm}); len(m) != 1 {
+		t.Fatalf("expected npm entry to match npm dependency, got %+v", m)
+	}
+	if m := set.Match(readers.Dependency{Name: "foo", Version: "1.0.0", Ecosystem: readers.EcosystemPyPI}); len(m) != 0 {
+		t.Fatalf("expected npm entry not to match PyPI dependency, got %+v", m)
+	}
+	if m := set.Match(readers.Dependency{Name: "foo", Version: "1.0.1", Ecosystem: readers.EcosystemNpm}); len(m) != 0 {
+		t.Fatalf("expected other version not to match, got %+v", m)
+	}
+	// legacy entries have no ecosystem and match any
+	if m := set.Match(readers.Dependency{Name: "bar", Version: "2.0.0", Ecosystem: readers.EcosystemCargo}); len(m) != 1 {
+		t.Fatalf("expected legacy entry to match, got %+v", m)
+	}
+}
+
+func TestMatch_LegacyNormalizedNames(t *testing.T) {
+	set := NewSet()
+	set.Add(Entry{Name: "Requests_Toolbelt", Version: "1.0.0", List: "legacy.txt"})
+	set.Add(Entry{Name: "Newtonsoft.Json", Version: "13.0.1", List: "legacy.txt"})
+
+	if m := set.Match(readers.Dependency{Name: "requests-toolbelt", Version: "1.0.0", Ecosystem: readers.EcosystemPyPI}); len(m) != 1 {
+		t.Fatalf("expected legacy entry to match the PEP 503 normalized name, got %+v", m)
+	}
+	if m := set.Match(readers.Dependency{Name: "Requests_Toolbelt", Version: "1.0.0", Ecosystem: readers.EcosystemPyPI}); len(m) != 1 {
+		t.Fatalf("expected legacy entry to match its listed name once, got %+v", m)
+	}
+	if m := set.Match(readers.Dependency{Name: "newtonsoft.json", Version: "13.0.1", Ecosystem: readers.EcosystemNuGet}); len(m) != 1 {
+		t.Fatalf("expected legacy entry to match the NuGet name case-insensitively, got %+v", m)
+	}
+	// NuGet ids compare case-insensitively whichever side is lowercase
+	lower := NewSet()
+	lower.Add(Entry{Name: "newtonsoft.json", Version: "13.0.1", List: "legacy.txt"})
+	if m := lower.Match(readers.Dependency{Name: "Newtonsoft.Json", Version: "13.0.1", Ecosystem: readers.EcosystemNuGet}); len(m) != 1 {
+		t.Fatalf("expected lowercase legacy entry to match the NuGet id, got %+v", m)
+	}
+	if m := lower.Match(readers.Dependency{Name: "Newtonsoft.Json", Version: "13.0.1", Ecosystem: readers.EcosystemNpm}); len(m) != 0 {
+		t.Fatalf("expected npm names to stay case-sensitive, got %+v", m)
+	}
+	// npm names are not normalized
+	if m := set.Match(readers.Dependency{Name: "requests-toolbelt", Version: "1.0.0", Ecosystem: readers.EcosystemNpm}); len(m) != 0 {
+		t.Fatalf("expected no npm match for another name, got %+v", m)
+	}
+}
+
+func TestMatchName_AnyEcosystem(t *testing.T) {
+	set := NewSet()
+	set.Add(Entry{Ecosystem: readers.EcosystemNpm, Name: "foo", Version: "1.0.0", List: "npm.txt"})
+	set.Add(Entry{Ecosystem: readers.EcosystemPyPI, Name: "foo", Version: ">=1.0", List: "pypi.txt"})
+	set.Add(Entry{Name: "foo", Version: "1.0.0", List: "legacy.txt"})
+
+	m := set.MatchName("foo", "1.0.0")
+	if len(m) != 3 || m[0].List != "legacy.txt" || m[1].List != "npm.txt" || m[2].List != "pypi.txt" {
+		t.Fatalf("expected legacy, npm and pypi entries once each, got %+v", m)
+	}
+	if m := set.MatchName("foo", "0.9.0"); len(m) != 0 {
+		t.Fatalf("expected no match for another version, got %+v", m)
+	}
+}
+
+func TestMatchName_LegacyRanges(t *testing.T) {
+	set := NewSet()
+	set.Add(Entry{Name: "foo", Version: "^1.0.0", List: "legacy.txt"})
+	set.Add(Entry{Name: "bar", Version: "*", List: "legacy.txt"})
+	set.Add(Entry{Name: "Requests_Toolbelt", Version: "1.0.0", List: "legacy.txt"})
+
+	// the range is semver, valid in npm, Cargo, Go and Composer alike
+	if m := set.MatchName("foo", "1.2.0"); len(m) != 1 || m[0].Version != "^1.0.0" {
+		t.Fatalf("expected the legacy range to match once, got %+v", m)
+	}
+	if m := set.MatchName("foo", "2.0.0"); len(m) != 0 {
+		t.Fatalf("expected no match outside the range, got %+v", m)
+	}
+	if m := set.MatchName("bar", "0.1.0"); len(m) != 1 {
+		t.Fatalf("expected the legacy wildcard to match once, got %+v", m)
+	}
+	if m := set.MatchName("requests-toolbelt", "1.0.0"); len(m) != 1 {
+		t.Fatalf("expected the legacy entry to match its normalized name, got %+v", m)
+	}
+}
+
+func TestMatch_VersionRanges(t *testing.T) {
+	set := NewSet()
+	for _, line := range []string{
+		"everything@*",
+		"left-pad@>=4.1.0 <4.1.3",
+		"pkg:maven/org.example/evil@[1.0,2.0)",
+		"pkg:pypi/evil@>=1.0,<1.2",
+	} {
+		e, err := ParseLine(line)
+		if err != nil {
+			t.Fatalf("ParseLine(%q): %v", line, err)
+		}
+		set.Add(e)
+	}
+
+	tests := []struct {
+		dep  readers.Dependency
+		want bool
+	}{
+		{readers.Dependency{Name: "everything", Version: "0.0.1", Ecosystem: readers.EcosystemNpm}, true},
+		{readers.Dependency{Name: "left-pad", Version: "4.1.2", Ecosystem: readers.EcosystemNpm}, true},
+		{readers.Dependency{Name: "left-pad", Version: "4.1.3", Ecosystem: readers.EcosystemNpm}, false},
+		{readers.Dependency{Name: "org.example:evil", Version: "1.5", Ecosystem: readers.EcosystemMaven}, true},
+		{readers.Dependency{Name: "org.example:evil", Version: "2.0", Ecosystem: readers.EcosystemMaven}, false},
+		{readers.Dependency{Name: "evil", Version: "1.1.post1", Ecosystem: readers.EcosystemPyPI}, true},
+		// the npm range is not valid PEP 440 and matches nothing for PyPI
+		{readers.Dependency{Name: "left-pad", Version: "4.1.2", Ecosystem: readers.EcosystemPyPI}, false},
+	}
+	for _, tt := range tests {
+		if got := len(set.Match(tt.dep)) > 0; got != tt.want {
+			t.Fatalf("Match(%+v) = %v, want %v", tt.dep, got, tt.want)
+		}
+	}
+}
+
+func TestParseLine_InvalidPurlRange(t *testing.T) {
+	if _, err := ParseLine("pkg:maven/org.example/evil@[1.0,2.0"); err == nil {
+		t.Fatalf("expected error for unclosed Maven range")
+	}
+}
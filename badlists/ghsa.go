@@ -0,0 +1,129 @@
+package badlists
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// LoadGitHubAdvisories adds advisories from a local clone of the GitHub
+// Advisory Database (https://github.com/github/advisory-database) to set.
+// root may be the clone or its advisories directory. Malware advisories are
+// always loaded; when minSeverity is set (low, moderate, high or critical),
+// other advisories of at least that severity are loaded too. It returns the
+// number of advisories loaded.
+func LoadGitHubAdvisories(set *Set, root, minSeverity string) (int, error) {
+	minRank := 0
+	if minSeverity != "" {
+		rank := SeverityRank(minSeverity)
+		if rank == 0 {
+			return 0, fmt.Errorf("unknown severity %q", minSeverity)
+		}
+		minRank = rank
+	}
+
+	dir := root
+	if info, err := os.Stat(filepath.Join(root, "advisories")); err == nil && info.IsDir() {
+		dir = filepath.Join(root, "advisories")
+	}
+
+	loaded := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			// one unreadable entry must not drop the rest of the database
+			log.Printf("Skipping %s: %v", path, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+			return nil
+		}
+		records, err := parseOSVRecords(data)
+		if err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+			return nil
+		}
+		for _, r := range records {
+			if !isMalwareAdvisory(r) && (minRank == 0 || SeverityRank(r.DatabaseSpecific.Severity) < minRank) {
+				continue
+			}
+			entries := osvEntries(r)
+			if len(entries) == 0 {
+				continue
+			}
+			addEntries(set, entries, root)
+			loaded++
+		}
+		return nil
+	})
+	return loaded, err
+}
+
+// GitHubAdvisoriesChangePaths returns the files whose modification times
+// tell when a clone of the database at root was last updated, since walking
+// the clone for its newest file takes as long as loading it. These are git's
+// index, which is rewritten whenever a pull, merge, reset or checkout changes
+// the work tree, HEAD, and the ref HEAD points to (or packed-refs, when the
+// ref is packed). Without a git directory, root itself is returned and
+// LatestModTime walks it.
+func GitHubAdvisoriesChangePaths(root string) []string {
+	gitDir := filepath.Join(root, ".git")
+	if filepath.Base(filepath.Clean(root)) == "advisories" {
+		if _, err := os.Stat(gitDir); err != nil {
+			gitDir = filepath.Join(filepath.Dir(filepath.Clean(root)), ".git")
+		}
+	}
+
+	candidates := []string{filepath.Join(gitDir, "index"), filepath.Join(gitDir, "HEAD")}
+	if head, err := os.ReadFile(filepath.Join(gitDir, "HEAD")); err == nil {
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: "); ok {
+			refPath := filepath.Join(gitDir, filepath.FromSlash(ref))
+			if _, err := os.Stat(refPath); err != nil {
+				refPath = filepath.Join(gitDir, "packed-refs")
+			}
+			candidates = append(candidates, refPath)
+		}
+	}
+
+	var paths []string
+	for _, p := range candidates {
+		if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return []string{root}
+	}
+	return paths
+}
+
+// isMalwareAdvisory reports whether an advisory describes a malicious
+// package rather than a vulnerability: its type is malware, or it is
+// classified as CWE-506 (Embedded Malicious Code). OpenSSF MAL- advisories
+// mirrored into the database are malware too.
+func isMalwareAdvisory(r osvRecord) bool {
+	return strings.EqualFold(r.DatabaseSpecific.Type, "malware") ||
+		slices.Contains(r.DatabaseSpecific.CWEIDs, "CWE-506") ||
+		strings.HasPrefix(r.ID, "MAL-")
+}
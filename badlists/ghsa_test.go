@@ -0,0 +1,143 @@
+package badlists
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joelcma/dewormer/readers"
+)
+
+func writeAdvisory(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing advisory: %v", err)
+	}
+}
+
+func githubAdvisoryDB(t *testing.T) string {
+	root := filepath.Join(t.TempDir(), "advisory-database")
+	writeAdvisory(t, root, "advisories/github-reviewed/2024/09/GHSA-aaaa-bbbb-cccc/GHSA-aaaa-bbbb-cccc.json", `{
+  "id": "GHSA-aaaa-bbbb-cccc",
+  "summary": "Malware in evil-pkg",
+  "affected": [{"package": {"ecosystem": "npm", "name": "evil-pkg"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]}],
+  "database_specific": {"cwe_ids": ["CWE-506"], "severity": "CRITICAL", "github_reviewed": true}
+}`)
+	writeAdvisory(t, root, "advisories/github-reviewed/2024/10/GHSA-dddd-eeee-ffff/GHSA-dddd-eeee-ffff.json", `{
+  "id": "GHSA-dddd-eeee-ffff",
+  "summary": "Prototype pollution in vuln-pkg",
+  "affected": [{"package": {"ecosystem": "npm", "name": "vuln-pkg"}, "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.0.0"}]}]}],
+  "database_specific": {"cwe_ids": ["CWE-1321"], "severity": "HIGH"}
+}`)
+	writeAdvisory(t, root, "advisories/unreviewed/2024/10/GHSA-gggg-hhhh-iiii/GHSA-gggg-hhhh-iiii.json", `{
+  "id": "GHSA-gggg-hhhh-iiii",
+  "summary": "Minor issue in meh-pkg",
+  "affected": [{"package": {"ecosystem": "PyPI", "name": "meh-pkg"}, "versions": ["1.0"]}],
+  "database_specific": {"severity": "LOW"}
+}`)
+	writeAdvisory(t, root, ".git/objects/x.json", "not an advisory")
+	return root
+}
+
+func TestLoadGitHubAdvisories_MalwareOnly(t *testing.T) {
+	root := githubAdvisoryDB(t)
+
+	set := NewSet()
+	n, err := LoadGitHubAdvisories(set, root, "")
+	if err != nil {
+		t.Fatalf("LoadGitHubAdvisories: %v", err)
+	}
+	if n != 1 || set.Len() != 1 {
+		t.Fatalf("expected only the malware advisory, got %d advisories, %d entries", n, set.Len())
+	}
+	m := set.Match(readers.Dependency{Name: "evil-pkg", Version: "3.1.4", Ecosystem: readers.EcosystemNpm})
+	if len(m) != 1 || m[0].Advisory != "GHSA-aaaa-bbbb-cccc" || m[0].List != "advisory-database" {
+		t.Fatalf("expected malware advisory to match, got %+v", m)
+	}
+}
+
+func TestLoadGitHubAdvisories_MinSeverity(t *testing.T) {
+	root := githubAdvisoryDB(t)
+
+	set := NewSet()
+	n, err := LoadGitHubAdvisories(set, filepath.Join(root, "advisories"), "high")
+	if err != nil {
+		t.Fatalf("LoadGitHubAdvisories: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected malware and high severity advisories, got %d", n)
+	}
+	if m := set.Match(readers.Dependency{Name: "vuln-pkg", Version: "1.9.0", Ecosystem: readers.EcosystemNpm}); len(m) != 1 {
+		t.Fatalf("expected high severity advisory to match, got %+v", m)
+	}
+	if m := set.Match(readers.Dependency{Name: "meh-pkg", Version: "1.0", Ecosystem: readers.EcosystemPyPI}); len(m) != 0 {
+		t.Fatalf("expected low severity advisory to be skipped, got %+v", m)
+	}
+
+	if _, err := LoadGitHubAdvisories(NewSet(), root, "severe"); err == nil {
+		t.Fatalf("expected error for unknown severity")
+	}
+}
+
+func TestGitHubAdvisoriesChangePaths(t *testing.T) {
+	root := githubAdvisoryDB(t)
+	if got := GitHubAdvisoriesChangePaths(root); len(got) != 1 || got[0] != root {
+		t.Fatalf("expected a clone without git metadata to be walked, got %v", got)
+	}
+}
+
+func TestGitHubAdvisoriesChangePaths_FastForward(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "init.defaultBranch=main"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	upstream := githubAdvisoryDB(t)
+	os.RemoveAll(filepath.Join(upstream, ".git"))
+	git(upstream, "init", "-q")
+	git(upstream, "add", ".")
+	git(upstream, "commit", "-q", "-m", "initial")
+	clone := filepath.Join(t.TempDir(), "advisory-database")
+	git(filepath.Dir(clone), "clone", "-q", upstream, clone)
+
+	paths := GitHubAdvisoriesChangePaths(clone)
+	if len(paths) == 0 || paths[0] == clone {
+		t.Fatalf("expected git metadata to be watched, got %v", paths)
+	}
+	if got := GitHubAdvisoriesChangePaths(filepath.Join(clone, "advisories")); len(got) != len(paths) || got[0] != paths[0] {
+		t.Fatalf("expected the advisories directory to use the clone's git metadata, got %v", got)
+	}
+
+	writeAdvisory(t, upstream, "advisories/github-reviewed/2024/11/GHSA-jjjj-kkkk-llll/GHSA-jjjj-kkkk-llll.json", `{"id": "GHSA-jjjj-kkkk-llll"}`)
+	git(upstream, "add", ".")
+	git(upstream, "commit", "-q", "-m", "new advisory")
+
+	// fetching alone doesn't change the advisories on disk; the
+	// fast-forward that follows must be noticed
+	git(clone, "fetch", "-q")
+	past := time.Now().Add(-time.Hour)
+	for _, p := range GitHubAdvisoriesChangePaths(clone) {
+		if err := os.Chtimes(p, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := LatestModTime(GitHubAdvisoriesChangePaths(clone))
+	git(clone, "merge", "-q", "--ff-only", "origin/main")
+
+	if after := LatestModTime(GitHubAdvisoriesChangePaths(clone)); !after.After(before) {
+		t.Fatalf("expected a fast-forward to be noticed, latest change %v before and %v after", before, after)
+	}
+}
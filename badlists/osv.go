@@ -0,0 +1,310 @@
+package badlists
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joelcma/dewormer/readers"
+	"github.com/joelcma/dewormer/versions"
+)
+
+// osvEcosystems maps OSV ecosystem names to purl types. Advisories for other
+// ecosystems are ignored.
+var osvEcosystems = map[string]string{
+	"npm":       readers.EcosystemNpm,
+	"Maven":     readers.EcosystemMaven,
+	"PyPI":      readers.EcosystemPyPI,
+	"Go":        readers.EcosystemGo,
+	"crates.io": readers.EcosystemCargo,
+	"RubyGems":  readers.EcosystemGem,
+	"Packagist": readers.EcosystemComposer,
+	"NuGet":     readers.EcosystemNuGet,
+}
+
+// osvRecord is the part of an OSV advisory (https://ossf.github.io/osv-schema/)
+// needed to match packages.
+type osvRecord struct {
+	ID         string        `json:"id"`
+	Summary    string        `json:"summary"`
+	Withdrawn  string        `json:"withdrawn"`
+	Affected   []osvAffected `json:"affected"`
+	References []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+	// DatabaseSpecific holds the fields the GitHub Advisory Database adds.
+	DatabaseSpecific struct {
+		Severity string   `json:"severity"`
+		CWEIDs   []string `json:"cwe_ids"`
+		Type     string   `json:"type"`
+	} `json:"database_specific"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Versions []string   `json:"versions"`
+	Ranges   []osvRange `json:"ranges"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+// parseOSV parses an OSV file, which holds a single advisory or an array of
+// them, into entries.
+func parseOSV(data []byte) ([]Entry, error) {
+	records, err := parseOSVRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, r := range records {
+		entries = append(entries, osvEntries(r)...)
+	}
+	return entries, nil
+}
+
+func parseOSVRecords(data []byte) ([]osvRecord, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []osvRecord
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("unmarshal OSV advisories: %w", err)
+		}
+		return records, nil
+	}
+
+	var r osvRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("unmarshal OSV advisory: %w", err)
+	}
+	return []osvRecord{r}, nil
+}
+
+// osvEntries returns an entry for every affected package of a supported
+// ecosystem. Withdrawn advisories have none.
+func osvEntries(r osvRecord) []Entry {
+	if r.Withdrawn != "" {
+		return nil
+	}
+
+	var refs []string
+	for _, ref := range r.References {
+		refs = append(refs, ref.URL)
+	}
+
+	var entries []Entry
+	for _, a := range r.Affected {
+		ecosystem, ok := osvEcosystems[a.Package.Ecosystem]
+		if !ok || a.Package.Name == "" {
+			continue
+		}
+		constraint, desc := osvConstraint(ecosystem, a)
+		if len(constraint) == 0 {
+			continue
+		}
+		entries = append(entries, Entry{
+			Ecosystem:  ecosystem,
+			Name:       a.Package.Name,
+			Version:    desc,
+			Advisory:   r.ID,
+			Summary:    r.Summary,
+			Severity:   strings.ToLower(r.DatabaseSpecific.Severity),
+			References: refs,
+			constraint: constraint,
+		})
+	}
+	return entries
+}
+
+// osvConstraint translates the affected versions and SEMVER/ECOSYSTEM ranges
+// of a package into a constraint and a readable description of it. GIT
+// ranges name commits rather than versions and are skipped.
+func osvConstraint(ecosystem string, a osvAffected) (versions.Union, string) {
+	var union versions.Union
+	var desc []string
+
+	for _, v := range a.Versions {
+		union = append(union, versions.Equal{Ecosystem: ecosystem, Version: v})
+		desc = append(desc, v)
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+		var open *versions.Interval
+		for _, e := range r.Events {
+			switch {
+			case e.Introduced != "":
+				open = &versions.Interval{Ecosystem: ecosystem}
+				if e.Introduced != "0" {
+					open.Lower = e.Introduced
+				}
+			case open != nil && e.Fixed != "":
+				open.Upper = e.Fixed
+			case open != nil && e.LastAffected != "":
+				open.Upper = e.LastAffected
+				open.UpperInclusive = true
+			default:
+				continue
+			}
+			if open.Upper != "" {
+				union = append(union, *open)
+				desc = append(desc, describeInterval(*open))
+				open = nil
+			}
+		}
+		if open != nil {
+			union = append(union, *open)
+			desc = append(desc, describeInterval(*open))
+		}
+	}
+
+	return union, strings.Join(desc, " || ")
+}
+
+func describeInterval(iv versions.Interval) string {
+	var parts []string
+	if iv.Lower != "" {
+		parts = append(parts, ">="+iv.Lower)
+	}
+	switch {
+	case iv.Upper != "" && iv.UpperInclusive:
+		parts = append(parts, "<="+iv.Upper)
+	case iv.Upper != "":
+		parts = append(parts, "<"+iv.Upper)
+	}
+	if len(parts) == 0 {
+		return "*"
+	}
+	return strings.Join(parts, " ")
+}
+
+// loadJSONFile loads a .json file of the list at listPath: a structured
+// list or OSV advisories. The file is read unless data is given.
+func loadJSONFile(set *Set, path, listPath string, data []byte) error {
+	if data == nil {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return err
+		}
+	}
+
+	var entries []Entry
+	var err error
+	if isStructuredJSON(data) {
+		entries, err = parseStructured(data, false, filepath.Base(listPath))
+	} else {
+		entries, err = parseOSV(data)
+	}
+	if err != nil {
+		return err
+	}
+	addEntries(set, entries, listPath)
+	return nil
+}
+
+// loadOSVDir loads every .json file below dir, skipping hidden directories
+// such as .git in a checkout of an advisory repository.
+func loadOSVDir(set *Set, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			// one unreadable entry must not drop the rest of the directory
+			log.Printf("Skipping %s: %v", path, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		if err := loadJSONFile(set, path, dir, nil); err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+		}
+		return nil
+	})
+}
+
+// loadOSVZip loads every .json file in a zip archive, such as the
+// per-ecosystem all.zip exports of osv.dev. The archive is read unless data
+// is given.
+func loadOSVZip(set *Set, path string, data []byte) error {
+	var zr *zip.Reader
+	if data == nil {
+		rc, err := zip.OpenReader(path)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		zr = &rc.Reader
+	} else {
+		var err error
+		if zr, err = zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".json") {
+			continue
+		}
+		entries, err := readZipOSV(f)
+		if err != nil {
+			log.Printf("Skipping %s in %s: %v", f.Name, path, err)
+			continue
+		}
+		addEntries(set, entries, path)
+	}
+	return nil
+}
+
+func readZipOSV(f *zip.File) ([]Entry, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	return parseOSV(data)
+}
+
+// addEntries adds entries read from the list at listPath.
+func addEntries(set *Set, entries []Entry, listPath string) {
+	listName := filepath.Base(filepath.Clean(listPath))
+	for _, e := range entries {
+		e.List, e.ListPath = listName, listPath
+		set.Add(e)
+	}
+}
@@ -0,0 +1,140 @@
+package badlists
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joelcma/dewormer/readers"
+)
+
+const osvAdvisory = `{
+  "schema_version": "1.5.0",
+  "id": "MAL-2024-1234",
+  "summary": "Malicious code in evil-pkg (npm)",
+  "affected": [
+    {
+      "package": {"ecosystem": "npm", "name": "evil-pkg"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "1.0.0"}, {"fixed": "1.2.0"}, {"introduced": "2.0.0"}, {"last_affected": "2.0.3"}]}],
+      "versions": ["0.9.1"]
+    },
+    {
+      "package": {"ecosystem": "Go", "name": "github.com/evil/mod"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+    },
+    {
+      "package": {"ecosystem": "Debian:12", "name": "libevil"},
+      "versions": ["1.0"]
+    }
+  ]
+}`
+
+func TestParseOSV(t *testing.T) {
+	entries, err := parseOSV([]byte(osvAdvisory))
+	if err != nil {
+		t.Fatalf("parseOSV: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries (Debian skipped), got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Advisory != "MAL-2024-1234" || entries[0].Summary != "Malicious code in evil-pkg (npm)" {
+		t.Fatalf("expected advisory metadata, got %+v", entries[0])
+	}
+	if entries[0].Version != "0.9.1 || >=1.0.0 <1.2.0 || >=2.0.0 <=2.0.3" {
+		t.Fatalf("unexpected version description %q", entries[0].Version)
+	}
+
+	set := NewSet()
+	for _, e := range entries {
+		set.Add(e)
+	}
+	tests := []struct {
+		dep  readers.Dependency
+		want bool
+	}{
+		{readers.Dependency{Name: "evil-pkg", Version: "0.9.1", Ecosystem: readers.EcosystemNpm}, true},
+		{readers.Dependency{Name: "evil-pkg", Version: "1.1.9", Ecosystem: readers.EcosystemNpm}, true},
+		{readers.Dependency{Name: "evil-pkg", Version: "1.2.0", Ecosystem: readers.EcosystemNpm}, false},
+		{readers.Dependency{Name: "evil-pkg", Version: "2.0.3", Ecosystem: readers.EcosystemNpm}, true},
+		{readers.Dependency{Name: "evil-pkg", Version: "2.0.4", Ecosystem: readers.EcosystemNpm}, false},
+		{readers.Dependency{Name: "evil-pkg", Version: "1.1.0", Ecosystem: readers.EcosystemPyPI}, false},
+		// OSV writes Go versions without the v prefix
+		{readers.Dependency{Name: "github.com/evil/mod", Version: "v0.0.0-20240101000000-abcdef123456", Ecosystem: readers.EcosystemGo}, true},
+	}
+	for _, tt := range tests {
+		if got := len(set.Match(tt.dep)) > 0; got != tt.want {
+			t.Fatalf("Match(%+v) = %v, want %v", tt.dep, got, tt.want)
+		}
+	}
+}
+
+func TestParseOSV_ArrayAndWithdrawn(t *testing.T) {
+	data := `[
+  {"id": "MAL-1", "affected": [{"package": {"ecosystem": "PyPI", "name": "Evil_Pkg"}, "versions": ["1.0"]}]},
+  {"id": "MAL-2", "withdrawn": "2024-01-01T00:00:00Z", "affected": [{"package": {"ecosystem": "PyPI", "name": "fine"}, "versions": ["1.0"]}]}
+]`
+	entries, err := parseOSV([]byte(data))
+	if err != nil {
+		t.Fatalf("parseOSV: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Advisory != "MAL-1" {
+		t.Fatalf("expected only the active advisory, got %+v", entries)
+	}
+
+	set := NewSet()
+	set.Add(entries[0])
+	if m := set.Match(readers.Dependency{Name: "evil-pkg", Version: "1.0.0", Ecosystem: readers.EcosystemPyPI}); len(m) != 1 {
+		t.Fatalf("expected normalized name and version to match, got %+v", m)
+	}
+}
+
+func TestLoad_OSVDirectoryAndZip(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	dir := filepath.Join(tmpDir, "malicious-packages")
+	nested := filepath.Join(dir, "osv", "malicious", "npm", "evil-pkg")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "MAL-2024-1234.json"), []byte(osvAdvisory), 0644); err != nil {
+		t.Fatalf("writing advisory: %v", err)
+	}
+	// hidden directories (e.g. .git) are not searched
+	hidden := filepath.Join(dir, ".git")
+	if err := os.MkdirAll(hidden, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(hidden, "x.json"), []byte("not json"), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	zipPath := filepath.Join(tmpDir, "all.zip")
+	zf, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("creating zip: %v", err)
+	}
+	zw := zip.NewWriter(zf)
+	w, err := zw.Create("MAL-1.json")
+	if err != nil {
+		t.Fatalf("zip entry: %v", err)
+	}
+	w.Write([]byte(`{"id": "MAL-1", "summary": "Malicious code in zipped", "affected": [{"package": {"ecosystem": "crates.io", "name": "zipped"}, "versions": ["0.1.0"]}]}`))
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip: %v", err)
+	}
+	zf.Close()
+
+	set, _ := Load([]string{dir, zipPath})
+	if set.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", set.Len())
+	}
+	m := set.Match(readers.Dependency{Name: "zipped", Version: "0.1.0", Ecosystem: readers.EcosystemCargo})
+	if len(m) != 1 || m[0].List != "all.zip" || m[0].Advisory != "MAL-1" {
+		t.Fatalf("expected zipped advisory to match, got %+v", m)
+	}
+	m = set.Match(readers.Dependency{Name: "evil-pkg", Version: "1.0.0", Ecosystem: readers.EcosystemNpm})
+	if len(m) != 1 || m[0].List != "malicious-packages" {
+		t.Fatalf("expected advisory from directory to match, got %+v", m)
+	}
+}
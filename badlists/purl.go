@@ -0,0 +1,71 @@
+package badlists
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/joelcma/dewormer/readers"
+)
+
+// ParsePurl parses a Package URL (pkg:type/namespace/name@version) into the
+// ecosystem and the package name as readers report it, e.g.
+//
+//	pkg:npm/%40scope/name@1.2.3         -> npm, @scope/name
+//	pkg:maven/org.example/artifact@1.0  -> maven, org.example:artifact
+//	pkg:golang/github.com/evil/mod@v1.2 -> golang, github.com/evil/mod
+//
+// Qualifiers and subpaths are ignored. The version may be empty.
+func ParsePurl(s string) (ecosystem, name, version string, err error) {
+	rest, ok := cutPrefixFold(s, "pkg:")
+	if !ok {
+		return "", "", "", fmt.Errorf("not a package URL: %q", s)
+	}
+	rest = strings.TrimLeft(rest, "/")
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+
+	typ, path, ok := strings.Cut(rest, "/")
+	if !ok || typ == "" || path == "" {
+		return "", "", "", fmt.Errorf("package URL %q has no type or name", s)
+	}
+	ecosystem = strings.ToLower(typ)
+
+	// the version follows the last @ of the final path segment; the npm
+	// scope @ is normally percent-encoded but may appear literally
+	if at := strings.LastIndex(path, "@"); at > strings.LastIndex(path, "/") && at > 0 {
+		if version, err = url.PathUnescape(path[at+1:]); err != nil {
+			return "", "", "", fmt.Errorf("package URL %q: %w", s, err)
+		}
+		path = path[:at]
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segments {
+		if segments[i], err = url.PathUnescape(seg); err != nil {
+			return "", "", "", fmt.Errorf("package URL %q: %w", s, err)
+		}
+	}
+	namespace := strings.Join(segments[:len(segments)-1], "/")
+	name = segments[len(segments)-1]
+	if name == "" {
+		return "", "", "", fmt.Errorf("package URL %q has no name", s)
+	}
+
+	switch {
+	case namespace == "":
+	case ecosystem == readers.EcosystemMaven:
+		name = namespace + ":" + name
+	default:
+		name = namespace + "/" + name
+	}
+
+	return ecosystem, name, version, nil
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
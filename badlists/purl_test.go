@@ -0,0 +1,38 @@
+package badlists
+
+import "testing"
+
+func TestParsePurl(t *testing.T) {
+	tests := []struct {
+		purl                     string
+		ecosystem, name, version string
+	}{
+		{"pkg:npm/%40rxap/ngx-bootstrap@19.0.3", "npm", "@rxap/ngx-bootstrap", "19.0.3"},
+		{"pkg:npm/@rxap/ngx-bootstrap@19.0.3", "npm", "@rxap/ngx-bootstrap", "19.0.3"},
+		{"pkg:npm/voip-callkit@1.0.2", "npm", "voip-callkit", "1.0.2"},
+		{"pkg:maven/org.example/evil-lib@1.0?type=jar", "maven", "org.example:evil-lib", "1.0"},
+		{"pkg:pypi/requests-toolbelt@1.0.0", "pypi", "requests-toolbelt", "1.0.0"},
+		{"pkg:golang/github.com/evil/mod@v1.2.3#sub/dir", "golang", "github.com/evil/mod", "v1.2.3"},
+		{"pkg:composer/evil/package@2.0.1", "composer", "evil/package", "2.0.1"},
+		{"PKG:NuGet/Evil.Package@1.0.0", "nuget", "Evil.Package", "1.0.0"},
+		{"pkg:cargo/evil-crate", "cargo", "evil-crate", ""},
+	}
+	for _, tt := range tests {
+		ecosystem, name, version, err := ParsePurl(tt.purl)
+		if err != nil {
+			t.Fatalf("ParsePurl(%q): %v", tt.purl, err)
+		}
+		if ecosystem != tt.ecosystem || name != tt.name || version != tt.version {
+			t.Fatalf("ParsePurl(%q) = %q, %q, %q; want %q, %q, %q",
+				tt.purl, ecosystem, name, version, tt.ecosystem, tt.name, tt.version)
+		}
+	}
+}
+
+func TestParsePurl_Invalid(t *testing.T) {
+	for _, s := range []string{"npm/foo@1.0", "pkg:npm", "pkg:npm/", "pkg:npm/%zz@1.0"} {
+		if _, _, _, err := ParsePurl(s); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package badlists
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/joelcma/dewormer/versions"
+	"gopkg.in/yaml.v3"
+)
+
+// structuredList is the JSON/YAML list format, which records why each
+// package is listed:
+//
+//	{"entries": [{"ecosystem": "npm", "name": "evil-pkg", "versions": ["1.0.0", ">=2.0.0 <2.1.0"],
+//	  "severity": "critical", "references": ["https://..."], "reason": "...", "added": "2024-11-28"}]}
+type structuredList struct {
+	Entries []structuredEntry `json:"entries" yaml:"entries"`
+}
+
+type structuredEntry struct {
+	Ecosystem  string     `json:"ecosystem" yaml:"ecosystem"`
+	Name       string     `json:"name" yaml:"name"`
+	Versions   stringList `json:"versions" yaml:"versions"`
+	Severity   string     `json:"severity" yaml:"severity"`
+	References stringList `json:"references" yaml:"references"`
+	Reason     string     `json:"reason" yaml:"reason"`
+	Added      string     `json:"added" yaml:"added"`
+}
+
+// stringList accepts a single string where a list is expected, so
+// `versions: "*"` works as well as `versions: ["*"]`.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = stringList{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = stringList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// isStructuredJSON reports whether a .json list is in the structured format
+// (an object with "entries") rather than an OSV advisory.
+func isStructuredJSON(data []byte) bool {
+	var probe struct {
+		Entries json.RawMessage `json:"entries"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Entries != nil
+}
+
+// parseStructured parses a structured list in JSON or YAML into entries.
+// Invalid entries are logged and skipped.
+func parseStructured(data []byte, isYAML bool, listName string) ([]Entry, error) {
+	var list structuredList
+	if isYAML {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parse YAML: %w", err)
+		}
+		if len(doc.Content) > 0 {
+			// a YAML list may also be a plain sequence of entries
+			var err error
+			if root := doc.Content[0]; root.Kind == yaml.SequenceNode {
+				err = root.Decode(&list.Entries)
+			} else {
+				err = root.Decode(&list)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unmarshal list: %w", err)
+			}
+		}
+	} else if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("unmarshal list: %w", err)
+	}
+
+	var entries []Entry
+	for i, se := range list.Entries {
+		es, err := structuredEntries(se)
+		if err != nil {
+			log.Printf("Skipping entry %d of %s: %v", i+1, listName, err)
+			continue
+		}
+		entries = append(entries, es...)
+	}
+	return entries, nil
+}
+
+// structuredEntries returns an entry for each version of a structured entry.
+func structuredEntries(se structuredEntry) ([]Entry, error) {
+	if se.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	if len(se.Versions) == 0 {
+		return nil, fmt.Errorf("%s: missing versions (use \"*\" for every version)", se.Name)
+	}
+
+	ecosystem := strings.ToLower(se.Ecosystem)
+	if purlType, ok := osvEcosystems[se.Ecosystem]; ok {
+		ecosystem = purlType // OSV spelling, e.g. PyPI or crates.io
+	}
+
+	var entries []Entry
+	for _, v := range se.Versions {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, fmt.Errorf("%s: empty version", se.Name)
+		}
+		if ecosystem != "" {
+			if _, err := versions.Parse(ecosystem, v); err != nil {
+				return nil, fmt.Errorf("%s: %w", se.Name, err)
+			}
+		}
+		entries = append(entries, Entry{
+			Ecosystem:  ecosystem,
+			Name:       se.Name,
+			Version:    v,
+			Severity:   strings.ToLower(se.Severity),
+			References: se.References,
+			Reason:     strings.TrimSpace(se.Reason), // block scalars end in a newline
+			Added:      se.Added,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,113 @@
+package badlists
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/joelcma/dewormer/readers"
+)
+
+func TestLoad_StructuredLists(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	jsonList := `{"entries": [
+  {"ecosystem": "npm", "name": "evil-pkg", "versions": ["1.0.0", ">=2.0.0 <2.1.0"], "severity": "Critical",
+   "references": ["https://example.com/evil-pkg"], "reason": "Exfiltrates npm tokens", "added": "2024-11-28"},
+  {"ecosystem": "npm", "name": "no-versions"},
+  {"ecosystem": "npm", "name": "bad-range", "versions": ">=abc"}
+]}`
+	yamlList := `- ecosystem: PyPI
+  name: Evil_Py
+  versions: "*"
+  severity: high
+  reason: Typosquat of a popular package
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "team.json"), []byte(jsonList), 0644); err != nil {
+		t.Fatalf("writing list: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "team.yaml"), []byte(yamlList), 0644); err != nil {
+		t.Fatalf("writing list: %v", err)
+	}
+
+	set, _ := Load([]string{filepath.Join(tmpDir, "team.json"), filepath.Join(tmpDir, "team.yaml")})
+	if set.Len() != 3 {
+		t.Fatalf("expected 3 entries (invalid ones skipped), got %d", set.Len())
+	}
+
+	m := set.Match(readers.Dependency{Name: "evil-pkg", Version: "2.0.5", Ecosystem: readers.EcosystemNpm})
+	if len(m) != 1 {
+		t.Fatalf("expected range entry to match, got %+v", m)
+	}
+	e := m[0]
+	if e.List != "team.json" || e.Severity != "critical" || e.Reason != "Exfiltrates npm tokens" ||
+		e.Added != "2024-11-28" || len(e.References) != 1 || e.References[0] != "https://example.com/evil-pkg" {
+		t.Fatalf("expected entry metadata, got %+v", e)
+	}
+
+	m = set.Match(readers.Dependency{Name: "evil-py", Version: "0.0.1", Ecosystem: readers.EcosystemPyPI})
+	if len(m) != 1 || m[0].Severity != "high" || m[0].List != "team.yaml" {
+		t.Fatalf("expected YAML entry to match, got %+v", m)
+	}
+}
+
+func TestIsStructuredJSON(t *testing.T) {
+	if !isStructuredJSON([]byte(`{"entries": []}`)) {
+		t.Fatalf("expected object with entries to be a structured list")
+	}
+	if isStructuredJSON([]byte(osvAdvisory)) || isStructuredJSON([]byte(`[{"id": "MAL-1"}]`)) {
+		t.Fatalf("expected OSV advisories not to be structured lists")
+	}
+}
+
+func TestParseStructured_YAML(t *testing.T) {
+	data := `# structured list
+entries:
+  - ecosystem: npm
+    name: "@scope/evil"   # quoted because of the @
+    versions: &evil ["1.0.0", '>=2.0.0 <2.1.0']
+    references:
+    - https://example.com/advisory#details
+    reason: |
+      Steals tokens.
+      # not a comment
+    added: 2024-11-28
+  - {ecosystem: pypi, name: foo, versions: "*"}
+  - ecosystem: npm
+    name: evil-twin
+    versions: *evil
+    reason: >
+      folded
+      text
+`
+	entries, err := parseStructured([]byte(data), true, "team.yaml")
+	if err != nil {
+		t.Fatalf("parseStructured: %v", err)
+	}
+	want := []Entry{
+		{Ecosystem: "npm", Name: "@scope/evil", Version: "1.0.0", References: []string{"https://example.com/advisory#details"}, Reason: "Steals tokens.\n# not a comment", Added: "2024-11-28"},
+		{Ecosystem: "npm", Name: "@scope/evil", Version: ">=2.0.0 <2.1.0", References: []string{"https://example.com/advisory#details"}, Reason: "Steals tokens.\n# not a comment", Added: "2024-11-28"},
+		// flow mappings and aliases are full YAML, not dropped entries
+		{Ecosystem: "pypi", Name: "foo", Version: "*"},
+		{Ecosystem: "npm", Name: "evil-twin", Version: "1.0.0", Reason: "folded text"},
+		{Ecosystem: "npm", Name: "evil-twin", Version: ">=2.0.0 <2.1.0", Reason: "folded text"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("unexpected entries:\n got %+v\nwant %+v", entries, want)
+	}
+}
+
+func TestParseStructured_YAMLErrors(t *testing.T) {
+	for _, data := range []string{
+		"entries:\n\t- name: tab",
+		"- name: \"unterminated",
+		"entries:\n  - name: a\n   versions: 1",
+		"just a scalar line",
+		"entries: *undefined",
+	} {
+		if _, err := parseStructured([]byte(data), true, "bad.yaml"); err == nil {
+			t.Fatalf("expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+// Exit codes of --ci and the scan subcommand.
+const (
+	exitClean      = 0
+	exitThreats    = 1
+	exitScanErrors = 2
+)
+
+// runPathScan scans exactly paths once, as --ci and the scan subcommand do,
+// and returns the exit code. The config file is optional: its lists and list
+// settings are used when it exists, but its scan paths and Maven
+// repositories are not. Scan state is neither read nor written.
+func runPathScan(configPath string, paths []string, format, outputPath string, failOnReaderErrors bool) int {
+	config := &Config{}
+	if _, err := os.Stat(configPath); err == nil {
+		if config, err = loadConfig(configPath); err != nil {
+			log.Printf("Failed to load config: %v", err)
+			return exitScanErrors
+		}
+	}
+
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			log.Printf("Cannot scan %s: %v", p, err)
+			return exitScanErrors
+		}
+	}
+	config.ScanPaths = paths
+	config.MavenRepositories = nil
+
+	report := runScan(config, scanOptions{Stateless: true})
+	if err := writePathScanReport(report, format, outputPath); err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return exitScanErrors
+	}
+	return ciExitCode(report, failOnReaderErrors)
+}
+
+// writePathScanReport writes the report of a path scan. Unlike a scheduled
+// scan, the text format is written too, so the result is on stdout.
+func writePathScanReport(report *Report, format, outputPath string) error {
+	if format != FormatText {
+		return writeReport(report, format, outputPath)
+	}
+	if outputPath == "" {
+		return writeText(os.Stdout, report)
+	}
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := writeText(f, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ciExitCode returns exitThreats if anything was found, or exitScanErrors if
+// the scan can't be trusted to be complete: no list was loaded, a list
+// couldn't be used or, with failOnReaderErrors, a file couldn't be parsed.
+func ciExitCode(report *Report, failOnReaderErrors bool) int {
+	switch {
+	case len(report.Findings) > 0:
+		return exitThreats
+	case len(report.Lists) == 0 || len(report.ListErrors) > 0:
+		return exitScanErrors
+	case failOnReaderErrors && len(report.ReaderErrors) > 0:
+		return exitScanErrors
+	}
+	return exitClean
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCIExitCode(t *testing.T) {
+	lists := []ReportList{{Name: "npm.txt", Path: "/lists/npm.txt"}}
+	readerErrs := []ReaderError{{File: "yarn.lock", Reader: "yarn.lock", Error: "bad"}}
+	tests := []struct {
+		name       string
+		report     Report
+		failReader bool
+		want       int
+	}{
+		{"clean", Report{Lists: lists}, false, exitClean},
+		{"threats", Report{Lists: lists, Findings: []Finding{{Package: "evil"}}, ReaderErrors: readerErrs}, true, exitThreats},
+		{"no lists", Report{}, false, exitScanErrors},
+		{"unreadable list", Report{Lists: lists, ListErrors: []ListError{{Path: "/lists/x.json", Error: "bad"}}}, false, exitScanErrors},
+		{"reader error ignored", Report{Lists: lists, ReaderErrors: readerErrs}, false, exitClean},
+		{"reader error fails", Report{Lists: lists, ReaderErrors: readerErrs}, true, exitScanErrors},
+	}
+	for _, tt := range tests {
+		if got := ciExitCode(&tt.report, tt.failReader); got != tt.want {
+			t.Fatalf("%s: exit code %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunPathScan_Stateless(t *testing.T) {
+	tmpDir := t.TempDir()
+	listsDir := filepath.Join(tmpDir, "lists")
+	project := filepath.Join(tmpDir, "project")
+	for _, dir := range []string{listsDir, project} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(listsDir, "go.txt"), []byte("pkg:golang/github.com/evil/mod@v1.2.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	goSum := filepath.Join(project, "go.sum")
+	if err := os.WriteFile(goSum, []byte("github.com/evil/mod v1.2.3 h1:abc=\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(cfg, lists string) { ConfigPathOverride, BadListsDirOverride = cfg, lists }(ConfigPathOverride, BadListsDirOverride)
+	configPath := filepath.Join(tmpDir, "config.json")
+	ConfigPathOverride = configPath
+	BadListsDirOverride = listsDir
+
+	// no config file is needed, and every run scans and reports again
+	out := filepath.Join(tmpDir, "out.txt")
+	for i := 0; i < 2; i++ {
+		if code := runPathScan(configPath, []string{project}, FormatText, out, false); code != exitThreats {
+			t.Fatalf("run %d: exit code %d, want %d", i+1, code, exitThreats)
+		}
+	}
+	text, _ := os.ReadFile(out)
+	want := "github.com/evil/mod@v1.2.3 in " + goSum + ":1 [go.sum] (matched: go.txt)\nFound 1 infected dependencies in 1 files.\n"
+	if string(text) != want {
+		t.Fatalf("text output = %q, want %q", text, want)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "scan_state.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no scan state to be written, got %v", err)
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no default config to be created, got %v", err)
+	}
+
+	if err := os.WriteFile(goSum, []byte("golang.org/x/sys v0.30.0 h1:jkl=\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code := runPathScan(configPath, []string{project}, FormatText, out, false); code != exitClean {
+		t.Fatalf("exit code %d for a clean project, want %d", code, exitClean)
+	}
+	if code := runPathScan(configPath, []string{filepath.Join(tmpDir, "missing")}, FormatText, out, false); code != exitScanErrors {
+		t.Fatalf("exit code %d for a missing path, want %d", code, exitScanErrors)
+	}
+}
+
+func TestCheckReportFlags(t *testing.T) {
+	tests := []struct {
+		format, output string
+		ci, ok         bool
+	}{
+		{FormatText, "", false, true},
+		{FormatJSON, "report.json", false, true},
+		{FormatText, "report.txt", false, false},
+		// --ci writes the text findings, like the scan command
+		{FormatText, "report.txt", true, true},
+		{"xml", "", true, false},
+	}
+	for _, tt := range tests {
+		if err := checkReportFlags(tt.format, tt.output, tt.ci); (err == nil) != tt.ok {
+			t.Fatalf("checkReportFlags(%q, %q, %v) = %v, want ok=%v", tt.format, tt.output, tt.ci, err, tt.ok)
+		}
+	}
+}
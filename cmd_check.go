@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joelcma/dewormer/badlists"
+	"github.com/joelcma/dewormer/readers"
+)
+
+// checkCommand runs `dewormer check [flags] <purl-or-name@version>...`,
+// which looks packages up in the configured lists. Packages are read from
+// stdin, one per line, when none are given or the only argument is "-". It
+// exits 0 when none is listed, 1 when any is, and 2 on invalid input or
+// unusable lists.
+func checkCommand(args []string, stdin io.Reader, stdout io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: dewormer check [flags] <purl-or-name@version>...")
+		fmt.Fprintln(fs.Output(), "\nChecks packages against the bad package lists, e.g. pkg:npm/left-pad@1.3.0 or left-pad@1.3.0.")
+		fmt.Fprintln(fs.Output(), "Reads packages from stdin, one per line, when none are given or the argument is -.")
+		fmt.Fprintln(fs.Output(), "Exits 0 when no package is listed, 1 when any is and 2 on errors.\n\nFlags:")
+		fs.PrintDefaults()
+	}
+	configFlag := fs.String("config", "", "Path to config.json (default: ~/.dewormer/config.json)")
+	badListsFlag := fs.String("bad-package-files", "", "Path to a directory containing bad-package list files (default: ~/.dewormer/bad_package_lists)")
+	fs.StringVar(badListsFlag, "b", "", "Shorthand for --bad-package-files")
+	if err := fs.Parse(args); err != nil {
+		return exitScanErrors
+	}
+
+	coords := fs.Args()
+	if len(coords) == 0 || len(coords) == 1 && coords[0] == "-" {
+		var err error
+		if coords, err = readCoordinates(stdin); err != nil {
+			fmt.Fprintf(fs.Output(), "Failed to read stdin: %v\n", err)
+			return exitScanErrors
+		}
+	}
+	if len(coords) == 0 {
+		fs.Usage()
+		return exitScanErrors
+	}
+
+	configPath := applyPathFlags(*configFlag, *badListsFlag)
+	config := &Config{}
+	if _, err := os.Stat(configPath); err == nil {
+		if config, err = loadConfig(configPath); err != nil {
+			fmt.Fprintf(fs.Output(), "Failed to load config: %v\n", err)
+			return exitScanErrors
+		}
+	}
+	lists := loadLists(config)
+
+	listed, failed := false, len(lists.paths) == 0 || len(lists.errs) > 0
+	for _, coord := range coords {
+		results, err := checkCoordinate(lists, coord)
+		switch {
+		case err != nil:
+			fmt.Fprintf(stdout, "%s: invalid: %v\n", coord, err)
+			failed = true
+		case len(results) == 0:
+			fmt.Fprintf(stdout, "%s: not listed\n", coord)
+		default:
+			listed = true
+			for _, result := range results {
+				fmt.Fprintf(stdout, "%s: LISTED (matched: %s)\n", coord, formatMatch(result))
+				for _, detail := range resultDetails(result) {
+					fmt.Fprintf(stdout, "    %s\n", detail)
+				}
+			}
+		}
+	}
+
+	switch {
+	case listed:
+		return exitThreats
+	case failed:
+		return exitScanErrors
+	}
+	return exitClean
+}
+
+// readCoordinates reads one package per line, skipping blank lines and
+// # comments.
+func readCoordinates(r io.Reader) ([]string, error) {
+	var coords []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		coords = append(coords, line)
+	}
+	return coords, scanner.Err()
+}
+
+// checkCoordinate returns the list entries matching a package given as a
+// purl or as name@version. A name without an ecosystem is looked up in
+// every ecosystem.
+func checkCoordinate(lists loadedLists, coord string) ([]ScanResult, error) {
+	dep, err := parseCoordinate(coord)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []badlists.Entry
+	if dep.Ecosystem == "" {
+		entries = lists.set.MatchName(dep.Name, dep.Version)
+	} else {
+		entries = lists.set.Match(dep)
+	}
+
+	var results []ScanResult
+	for _, e := range entries {
+		d := dep
+		if d.Ecosystem == "" {
+			d.Ecosystem = e.Ecosystem
+		}
+		result := newResult(d, e, "")
+		result.ListSignature = lists.signatureOf(e.ListPath)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// parseCoordinate parses a purl (pkg:npm/left-pad@1.3.0) or name@version
+// into a dependency. The version must be exact.
+func parseCoordinate(coord string) (readers.Dependency, error) {
+	var dep readers.Dependency
+	if strings.HasPrefix(strings.ToLower(coord), "pkg:") {
+		ecosystem, name, version, err := badlists.ParsePurl(coord)
+		if err != nil {
+			return dep, err
+		}
+		dep = readers.Dependency{Name: name, Version: version, Ecosystem: ecosystem}
+	} else {
+		at := strings.LastIndex(coord, "@")
+		if at <= 0 {
+			return dep, fmt.Errorf("expected a package URL or name@version")
+		}
+		dep = readers.Dependency{Name: coord[:at], Version: coord[at+1:]}
+	}
+
+	switch {
+	case dep.Version == "":
+		return dep, fmt.Errorf("missing version")
+	case strings.ContainsAny(dep.Version, " *^~<>=|,"):
+		return dep, fmt.Errorf("version %q is a range; check needs an exact version", dep.Version)
+	}
+	return dep, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	listsDir := filepath.Join(tmpDir, "lists")
+	if err := os.MkdirAll(listsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	list := `{"entries": [{"ecosystem": "npm", "name": "evil-pkg", "versions": ">=2.0.0 <2.1.0", "severity": "critical", "reason": "Exfiltrates npm tokens"}]}`
+	if err := os.WriteFile(filepath.Join(listsDir, "team.json"), []byte(list), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(listsDir, "legacy.txt"), []byte("foo@^1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(cfg, lists string) { ConfigPathOverride, BadListsDirOverride = cfg, lists }(ConfigPathOverride, BadListsDirOverride)
+	flags := []string{"--config", filepath.Join(tmpDir, "config.json"), "-b", listsDir}
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		code   int
+		output string
+	}{
+		{"purl listed", []string{"pkg:npm/evil-pkg@2.0.5"}, "", exitThreats,
+			"pkg:npm/evil-pkg@2.0.5: LISTED (matched: team.json, severity: critical)\n    reason: Exfiltrates npm tokens\n"},
+		{"name without ecosystem", []string{"evil-pkg@2.0.5"}, "", exitThreats,
+			"evil-pkg@2.0.5: LISTED (matched: team.json, severity: critical)\n    reason: Exfiltrates npm tokens\n"},
+		{"legacy range", []string{"foo@1.2.0", "pkg:npm/foo@1.2.0", "foo@2.0.0"}, "", exitThreats,
+			"foo@1.2.0: LISTED (matched: legacy.txt)\npkg:npm/foo@1.2.0: LISTED (matched: legacy.txt)\nfoo@2.0.0: not listed\n"},
+		{"not listed", []string{"pkg:npm/evil-pkg@2.1.0", "pkg:pypi/evil-pkg@2.0.5"}, "", exitClean,
+			"pkg:npm/evil-pkg@2.1.0: not listed\npkg:pypi/evil-pkg@2.0.5: not listed\n"},
+		{"stdin", nil, "# bump\nleft-pad@1.3.0\n\npkg:npm/evil-pkg@2.0.0\n", exitThreats,
+			"left-pad@1.3.0: not listed\npkg:npm/evil-pkg@2.0.0: LISTED (matched: team.json, severity: critical)\n    reason: Exfiltrates npm tokens\n"},
+		{"invalid", []string{"left-pad", "left-pad@^1.0.0"}, "", exitScanErrors,
+			"left-pad: invalid: expected a package URL or name@version\nleft-pad@^1.0.0: invalid: version \"^1.0.0\" is a range; check needs an exact version\n"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		code := checkCommand(append(append([]string{}, flags...), tt.args...), strings.NewReader(tt.stdin), &out)
+		if code != tt.code {
+			t.Fatalf("%s: exit code %d, want %d", tt.name, code, tt.code)
+		}
+		if out.String() != tt.output {
+			t.Fatalf("%s: output %q, want %q", tt.name, out.String(), tt.output)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// scanCommand runs `dewormer scan [flags] <path>...`, which scans the given
+// directories or dependency files with the configured lists and prints what
+// it finds to stdout. The scan state is left alone, so ad-hoc scans don't
+// affect scheduled ones.
+func scanCommand(args []string) int {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: dewormer scan [flags] <path>...")
+		fmt.Fprintln(fs.Output(), "\nScans directories or individual dependency files and prints the findings.")
+		fmt.Fprintln(fs.Output(), "Exits 0 when clean, 1 when threats are found and 2 on scan errors.\n\nFlags:")
+		fs.PrintDefaults()
+	}
+	configFlag := fs.String("config", "", "Path to config.json (default: ~/.dewormer/config.json)")
+	badListsFlag := fs.String("bad-package-files", "", "Path to a directory containing bad-package list files (default: ~/.dewormer/bad_package_lists)")
+	fs.StringVar(badListsFlag, "b", "", "Shorthand for --bad-package-files")
+	format := fs.String("format", FormatText, "Output format: text, json or sarif")
+	outputPath := fs.String("output", "", "Write the output to this file instead of stdout")
+	fs.StringVar(outputPath, "o", "", "Shorthand for --output")
+	failOnReaderErrors := fs.Bool("fail-on-reader-errors", false, "Exit 2 when a dependency file can't be parsed")
+	if err := fs.Parse(args); err != nil {
+		return exitScanErrors
+	}
+
+	if *format != FormatText && *format != FormatJSON && *format != FormatSARIF {
+		fmt.Fprintf(fs.Output(), "Unknown --format %q: use text, json or sarif\n", *format)
+		return exitScanErrors
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitScanErrors
+	}
+
+	configPath := applyPathFlags(*configFlag, *badListsFlag)
+	return runPathScan(configPath, fs.Args(), *format, expandTilde(*outputPath), *failOnReaderErrors)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanCommand_Lockfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	listsDir := filepath.Join(tmpDir, "lists")
+	if err := os.MkdirAll(listsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(listsDir, "gems.txt"), []byte("pkg:gem/rest-client@1.6.13\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lockfile := filepath.Join(tmpDir, "Gemfile.lock")
+	data := "GEM\n  remote: https://rubygems.org/\n  specs:\n    rest-client (1.6.13)\n"
+	if err := os.WriteFile(lockfile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the Gemfile.lock of another project next to it is not scanned
+	if err := os.MkdirAll(filepath.Join(tmpDir, "other"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "other", "Gemfile.lock"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(cfg, lists string) { ConfigPathOverride, BadListsDirOverride = cfg, lists }(ConfigPathOverride, BadListsDirOverride)
+	out := filepath.Join(tmpDir, "report.json")
+	args := []string{"--config", filepath.Join(tmpDir, "config.json"), "-b", listsDir, "--format", "json", "-o", out, lockfile}
+	if code := scanCommand(args); code != exitThreats {
+		t.Fatalf("exit code %d, want %d", code, exitThreats)
+	}
+
+	raw, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(raw, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].File != lockfile || report.Findings[0].Line != 4 {
+		t.Fatalf("expected rest-client in the given lockfile only, got %+v", report.Findings)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "scan_state.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected scan state to be left alone, got %v", err)
+	}
+}
+
+func TestScanCommand_Usage(t *testing.T) {
+	if code := scanCommand([]string{"--format", "json"}); code != exitScanErrors {
+		t.Fatalf("expected a usage error without paths, got exit code %d", code)
+	}
+	if code := scanCommand([]string{"--format", "xml", "."}); code != exitScanErrors {
+		t.Fatalf("expected an error for an unknown format, got exit code %d", code)
+	}
+}
@@ -2,7 +2,11 @@ module github.com/joelcma/dewormer
 
 go 1.22.2
 
-require github.com/gen2brain/beeep v0.11.1
+require (
+	github.com/gen2brain/beeep v0.11.1
+	golang.org/x/crypto v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	git.sr.ht/~jackmordaunt/go-toast v1.1.2 // indirect
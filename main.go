@@ -1,8 +1,10 @@
 package main
 
 import (
-	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -12,7 +14,10 @@ import (
 	"time"
 
 	"github.com/gen2brain/beeep"
+	"github.com/joelcma/dewormer/badlists"
 	"github.com/joelcma/dewormer/readers"
+	"github.com/joelcma/dewormer/remote"
+	"github.com/joelcma/dewormer/signature"
 	statepkg "github.com/joelcma/dewormer/state"
 )
 
@@ -31,22 +36,79 @@ var BadListsDirOverride string
 type Config struct {
 	ScanPaths       []string `json:"scan_paths"`
 	BadPackageLists []string `json:"bad_package_lists"`
+	// MavenRepositories are local Maven repositories (e.g. ~/.m2/repository)
+	// whose cached artifacts are checked on every scan.
+	MavenRepositories []string `json:"maven_repositories,omitempty"`
+	// ScanNodeModules enables reading installed node_modules/**/package.json
+	// manifests in addition to lockfiles.
+	ScanNodeModules bool `json:"scan_node_modules,omitempty"`
+	// GitHubAdvisoryDatabase is a local clone of the GitHub Advisory
+	// Database whose malware advisories are loaded as a bad package list.
+	GitHubAdvisoryDatabase string `json:"github_advisory_database,omitempty"`
+	// GitHubAdvisoryMinSeverity additionally loads non-malware advisories of
+	// at least this severity (low, moderate, high or critical).
+	GitHubAdvisoryMinSeverity string `json:"github_advisory_min_severity,omitempty"`
+	// RemoteLists are downloaded into the lists directory before each scan
+	// and refreshed when their refresh interval has passed.
+	RemoteLists []remote.List `json:"remote_lists,omitempty"`
+	// TrustedKeys are the ed25519 public keys (minisign or raw base64) that
+	// list signatures are checked against.
+	TrustedKeys []string `json:"trusted_keys,omitempty"`
+	// SignaturePolicy is off, warn or require. It defaults to warn when
+	// trusted keys are configured and off otherwise.
+	SignaturePolicy string `json:"signature_policy,omitempty"`
 }
 
 type ScanResult struct {
 	Package string
 	Version string
-	File    string
-	List    string
+	// Ecosystem is the purl type of the package (npm, maven, pypi, ...).
+	Ecosystem string
+	File      string
+	// Location is where in File the package was found (e.g. the
+	// package-lock.json install path node_modules/a/node_modules/b).
+	Location string
+	// Line is the line of the entry in File, or 0 when unknown.
+	Line int
+	List string
+	// ListPath is the path of List, which tells apart lists with the same
+	// file name.
+	ListPath string
+	// Advisory and Summary identify the OSV advisory that listed the
+	// package, if any.
+	Advisory string
+	Summary  string
+	// Severity, Reason, References and Added are the metadata of the list
+	// entry, when the list provides them.
+	Severity   string
+	Reason     string
+	References []string
+	Added      string
+	// ListSignature is the signature status of List for this run
+	// (verified, unsigned or invalid), when signatures are checked.
+	ListSignature signature.Status
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "scan":
+			os.Exit(scanCommand(os.Args[2:]))
+		case "check":
+			os.Exit(checkCommand(os.Args[2:], os.Stdin, os.Stdout))
+		}
+	}
+
 	// CLI flags
 	var showVersion bool
 	var intervalFlag string
 	var configFlag string
 	var badListsFlag string
 	var forceRescan bool
+	var format string
+	var outputPath string
+	var ciMode bool
+	var failOnReaderErrors bool
 	flag.BoolVar(&showVersion, "version", false, "Show version and exit")
 	flag.BoolVar(&showVersion, "v", false, "Show version and exit (shorthand)")
 	flag.StringVar(&intervalFlag, "interval", "", "Run periodically with this interval (e.g. 12h). If omitted the program performs a single run and exits.")
@@ -56,27 +118,30 @@ func main() {
 	flag.BoolVar(&forceRescan, "r", false, "Shorthand for --force-rescan")
 	flag.StringVar(&badListsFlag, "b", "", "Shorthand for --bad-package-files")
 	flag.StringVar(&intervalFlag, "i", "", "Shorthand for --interval")
+	flag.StringVar(&format, "format", FormatText, "Report format: text (log output only), json or sarif")
+	flag.StringVar(&outputPath, "output", "", "Write the report to this file instead of stdout")
+	flag.StringVar(&outputPath, "o", "", "Shorthand for --output")
+	flag.BoolVar(&ciMode, "ci", false, "Scan the paths given as arguments (default: the current directory) once, without notifications or scan state, and exit 0 (clean), 1 (threats found) or 2 (scan errors)")
+	flag.BoolVar(&failOnReaderErrors, "fail-on-reader-errors", false, "With --ci, exit 2 when a dependency file can't be parsed")
 	flag.Parse()
 	if showVersion {
 		fmt.Println(Version)
 		os.Exit(0)
 	}
-	// Determine which config path to use. CLI flag takes precedence.
-	var configPath string
-	if configFlag != "" {
-		configPath = expandTilde(configFlag)
-	} else {
-		configPath = getConfigPath()
+	if err := checkReportFlags(format, outputPath, ciMode); err != nil {
+		log.Fatal(err)
 	}
+	outputPath = expandTilde(outputPath)
+	if ciMode && intervalFlag != "" {
+		log.Fatalf("--ci and --interval can't be combined")
+	}
+	if !ciMode && flag.NArg() > 0 {
+		log.Fatalf("Unexpected arguments %q: scan paths are only accepted with --ci", flag.Args())
+	}
+	configPath := applyPathFlags(configFlag, badListsFlag)
 
-	// Make the resolved path available to helper functions that call getConfigPath().
-	ConfigPathOverride = configPath
-
-	// Bad lists dir flagged by user? make it available to runScan through a global
-	// override variable (helpers call os.UserHomeDir which respects HOME, so this
-	// keeps parity with getConfigPath override semantics).
-	if badListsFlag != "" {
-		BadListsDirOverride = expandTilde(badListsFlag)
+	if ciMode {
+		os.Exit(runPathScan(configPath, flag.Args(), format, outputPath, failOnReaderErrors))
 	}
 
 	// Check if config exists, create default if not
@@ -109,8 +174,10 @@ func main() {
 		log.Printf("Dewormer started. Scanning every %s", interval)
 	}
 
+	opts := scanOptions{ForceRescan: forceRescan, Notify: true}
+
 	// Run initial scan immediately
-	runScan(config, forceRescan)
+	emitReport(runScan(config, opts), format, outputPath)
 
 	// If --interval wasn't provided then we run a single scan and exit.
 	if intervalFlag == "" {
@@ -123,8 +190,52 @@ func main() {
 	defer ticker.Stop()
 
 	for range ticker.C {
-		runScan(config, forceRescan)
+		emitReport(runScan(config, opts), format, outputPath)
+	}
+}
+
+// checkReportFlags validates --format and --output. A scheduled scan only
+// logs its findings in the text format, so there is nothing to write to
+// --output; --ci writes the text findings like the scan command does.
+func checkReportFlags(format, outputPath string, ciMode bool) error {
+	if format != FormatText && format != FormatJSON && format != FormatSARIF {
+		return fmt.Errorf("unknown --format %q: use text, json or sarif", format)
+	}
+	if outputPath != "" && format == FormatText && !ciMode {
+		return errors.New("--output needs a machine-readable --format (json or sarif), or --ci")
 	}
+	return nil
+}
+
+// emitReport writes the report of a scan; with --interval each scan replaces
+// the report file, or appends another document to stdout.
+func emitReport(report *Report, format, outputPath string) {
+	if err := writeReport(report, format, outputPath); err != nil {
+		log.Printf("Failed to write report: %v", err)
+	}
+}
+
+// applyPathFlags applies --config and --bad-package-files and returns the
+// config path to use.
+func applyPathFlags(configFlag, badListsFlag string) string {
+	// Determine which config path to use. CLI flag takes precedence.
+	var configPath string
+	if configFlag != "" {
+		configPath = expandTilde(configFlag)
+	} else {
+		configPath = getConfigPath()
+	}
+
+	// Make the resolved path available to helper functions that call getConfigPath().
+	ConfigPathOverride = configPath
+
+	// Bad lists dir flagged by user? make it available to runScan through a global
+	// override variable (helpers call os.UserHomeDir which respects HOME, so this
+	// keeps parity with getConfigPath override semantics).
+	if badListsFlag != "" {
+		BadListsDirOverride = expandTilde(badListsFlag)
+	}
+	return configPath
 }
 
 func getConfigPath() string {
@@ -191,6 +302,9 @@ func createDefaultConfig(path string) error {
 		BadPackageLists: []string{
 			filepath.Join(homeDir, ".dewormer", "bad_package_lists", "npm-malicious.txt"),
 		},
+		MavenRepositories: []string{
+			filepath.Join(homeDir, ".m2", "repository"),
+		},
 	}
 
 	// Create bad package lists directory
@@ -232,87 +346,107 @@ func loadConfig(path string) (*Config, error) {
 	return &config, nil
 }
 
-func runScan(config *Config, forceRescan bool) {
+// scanOptions controls how a single scan runs. The zero value scans
+// incrementally against the saved scan state and doesn't notify. Scheduled
+// scans set Notify, and ForceRescan with --force-rescan; --ci and the scan
+// command run Stateless.
+type scanOptions struct {
+	// ForceRescan scans every file even if the scan state says it is
+	// unchanged. The state is still saved, so the next run is incremental
+	// again. With --interval it applies to every scan.
+	ForceRescan bool
+	// Stateless neither reads nor saves the scan state: every file is
+	// scanned, nothing is skipped on the strength of an earlier run, and
+	// later runs are unaffected.
+	Stateless bool
+	// Notify shows a desktop notification when threats are found, naming
+	// the most severe finding. Clean scans don't notify.
+	Notify bool
+}
+
+// runScan scans the configured paths, logs and notifies about what it finds
+// and returns a report of the scan.
+func runScan(config *Config, opts scanOptions) *Report {
 	log.Println("Starting scan...")
 	startTime := time.Now()
-	if forceRescan {
+	report := newReport(config, startTime)
+	if opts.ForceRescan {
 		log.Println("Force rescan enabled; ignoring scan state for this run")
 	}
 
-	// Build the list of bad package list files to load.
-	// We use any entries in config.BadPackageLists plus every file found in
-	// ~/.dewormer/bad_package_lists so users don't need to enumerate each file.
-	listPaths := make([]string, 0, len(config.BadPackageLists))
-	// add configured lists first (may be empty)
-	listPaths = append(listPaths, config.BadPackageLists...)
-
-	// also include every file under ~/.dewormer/bad_package_lists (or an
-	// override directory supplied by --bad-package-files).
-	listsDir := ""
-	if BadListsDirOverride != "" {
-		listsDir = BadListsDirOverride
-	} else if home, err := os.UserHomeDir(); err == nil {
-		listsDir = filepath.Join(home, ".dewormer", "bad_package_lists")
-	}
-
-	if listsDir != "" {
-		if entries, err := os.ReadDir(listsDir); err == nil {
-			for _, e := range entries {
-				if e.IsDir() {
-					continue
-				}
-				full := filepath.Join(listsDir, e.Name())
-				// avoid duplicates
-				found := false
-
-				for _, p := range listPaths {
-					if p == full {
-						found = true
-						break
-					}
-				}
-				if !found {
-					listPaths = append(listPaths, full)
-				}
-			}
-		}
-	}
-
-	// Load all bad packages
-	badPackages := loadBadPackages(listPaths)
-	log.Printf("Loaded %d bad packages from %d lists", len(badPackages), len(listPaths))
+	lists := loadLists(config)
+	badPackages := lists.set
+	report.addLists(lists.paths, lists.signatures)
+	report.addListErrors(lists.errs)
+	report.BadPackages = badPackages.Len()
 
 	// compute latest modtime of the bad-package lists; we'll use this to
 	// determine whether a given package file needs scanning. If any list has
 	// changed more recently than the package file we should check it.
-	var latestListMod time.Time
+	// Signatures count as list changes too, because under require a list
+	// that becomes verified adds packages without itself changing. The
+	// advisory database is too large to walk on every scan, so only the git
+	// metadata that a pull rewrites is checked for it.
+	var modPaths []string
+	for _, p := range lists.paths {
+		if p == lists.advisoryDB {
+			modPaths = append(modPaths, badlists.GitHubAdvisoriesChangePaths(p)...)
+			continue
+		}
+		modPaths = append(modPaths, p)
+		if lists.policy != signature.PolicyOff {
+			if sig := signature.SignaturePath(p); sig != "" {
+				modPaths = append(modPaths, sig)
+			}
+		}
+	}
+	latestListMod := badlists.LatestModTime(modPaths)
+	// files checked against a different set of lists or list settings are
+	// rescanned: their cached findings may name lists that are gone
+	fingerprint := listsFingerprint(lists.paths, string(lists.policy), config.GitHubAdvisoryMinSeverity)
 
 	// Use same config dir as getConfigPath to determine where to persist
 	// the scan state so it's always colocated with the config file.
 	cfgPath := getConfigPath()
 	scanStatePath := filepath.Join(filepath.Dir(cfgPath), "scan_state.json")
 
-	log.Printf("Loading scan state from %s", scanStatePath)
-	state := statepkg.LoadScanState(scanStatePath)
-	for _, p := range listPaths {
-		st, err := os.Stat(p)
-		if err != nil {
-			continue
-		}
-
-		// (state already loaded)
-		if st.ModTime().After(latestListMod) {
-			latestListMod = st.ModTime()
-		}
+	var state statepkg.ScanState
+	if opts.Stateless {
+		log.Println("Scan state disabled for this run")
+		state = make(statepkg.ScanState)
+		scanStatePath = ""
+	} else {
+		log.Printf("Loading scan state from %s", scanStatePath)
+		state = statepkg.LoadScanState(scanStatePath)
 	}
 
 	// initialize available readers
-	readersList := []readers.DependencyReader{
+	registry := readers.NewRegistry(
 		readers.NewPackageLockReader(),
+		readers.NewYarnLockReader(),
+		readers.NewPnpmLockReader(),
 		readers.NewPomReader(),
-	}
+		readers.NewGradleLockfileReader(),
+		readers.NewGradleVersionCatalogReader(),
+		readers.NewRequirementsReader(),
+		readers.NewPoetryLockReader(),
+		readers.NewPipfileLockReader(),
+		readers.NewUvLockReader(),
+		readers.NewGoModReader(),
+		readers.NewGoSumReader(),
+		readers.NewCargoLockReader(),
+		readers.NewGemfileLockReader(),
+		readers.NewComposerLockReader(),
+		readers.NewNuGetLockReader(),
+		readers.NewProjectFileReader(),
+	)
+
+	nodeModulesReader := readers.NewNodeModulesReader()
 
 	var results []ScanResult
+	// installed holds findings from node_modules manifests; they are merged
+	// into results once all lockfiles have been read.
+	var installed []ScanResult
 	filesScanned := 0
 
 	// Scan all configured paths
@@ -330,46 +464,101 @@ func runScan(config *Config, forceRescan bool) {
 			}
 
 			if info.IsDir() {
-				return nil
-			}
-
-			fileFound := false
-			for _, r := range readersList {
-				if r.Supports(info.Name()) {
-					// decide whether we need to scan this file using persisted
-					// state. shouldScan returns the normalized path, last scan time
-					// and whether a scan is required.
-					absPath, lastScan, needScan := shouldScan(path, info, latestListMod, state, forceRescan)
-					if !needScan {
-						log.Printf("Skipping scan for %s (no changes since last scan at %s)", path, lastScan)
-						return nil
-					}
-
-					filesScanned++
-					deps, err := r.ReadDependencies(path)
+				if config.ScanNodeModules && info.Name() == "node_modules" {
+					projectDir := filepath.Dir(path)
+					deps, err := nodeModulesReader.ReadTree(projectDir)
 					if err != nil {
-						log.Printf("could not read dependencies with %s: %v", r.Name(), err)
+						log.Printf("could not read dependencies with %s: %v", nodeModulesReader.Name(), err)
+						report.addReaderError(path, nodeModulesReader.Name(), err)
 					} else {
-						matches := findMatches(deps, badPackages, path)
-						results = append(results, matches...)
+						log.Printf("Scanned: %s (%d installed packages)", path, len(deps))
+						installed = append(installed, findMatches(deps, badPackages, projectDir)...)
 					}
+					// nested node_modules were read by ReadTree
+					return filepath.SkipDir
+				}
+				return nil
+			}
 
-					// Mark file as scanned now (store UnixNano)
-					state[absPath] = time.Now().UnixNano()
-
-					fileFound = true
-					break
+			r := registry.Lookup(info.Name())
+			if r == nil {
+				if path == scanPath {
+					log.Printf("No reader supports %s", path)
 				}
+				return nil
 			}
 
-			if fileFound {
-				log.Printf("Scanned: %s", path)
+			// decide whether we need to scan this file using persisted
+			// state. shouldScan returns the normalized path, last scan time
+			// and whether a scan is required.
+			absPath, lastScan, needScan := shouldScan(path, info, latestListMod, fingerprint, state, opts.ForceRescan)
+			if !needScan {
+				// The file is unchanged, but whatever was bad in it
+				// last time is still on disk: replay cached findings.
+				cached := resultsFromState(path, state[absPath])
+				log.Printf("Skipping scan for %s (no changes since last scan at %s, %d cached findings)", path, lastScan, len(cached))
+				report.FilesSkipped++
+				results = append(results, cached...)
+				return nil
+			}
+
+			filesScanned++
+			var deps []readers.Dependency
+			var sources []string
+			if sr, ok := r.(readers.SourceReader); ok {
+				deps, sources, err = sr.ReadDependenciesAndSources(path)
+			} else {
+				deps, err = r.ReadDependencies(path)
+			}
+			if err != nil {
+				// Don't record the file as scanned so it is retried
+				// on the next run instead of being skipped as clean.
+				log.Printf("could not read dependencies with %s: %v", r.Name(), err)
+				report.addReaderError(path, r.Name(), err)
+				delete(state, absPath)
+			} else {
+				matches := findMatches(deps, badPackages, path)
+				results = append(results, matches...)
+
+				// Mark file as scanned now and remember what we found
+				state[absPath] = statepkg.FileState{
+					ScannedAt: time.Now().UnixNano(),
+					Reader:    r.Name(),
+					Findings:  findingsForState(matches),
+					Version:   statepkg.FormatVersion,
+					Lists:     fingerprint,
+					Sources:   statepkg.NewSources(sources),
+				}
 			}
 
+			log.Printf("Scanned: %s", path)
+
 			return nil
 		})
 	}
 
+	results = mergeInstalled(results, installed)
+
+	// Local Maven repositories are always walked in full: they are large
+	// trees rather than single files, so scan state does not apply.
+	repoReader := readers.NewMavenRepositoryReader()
+	for _, repo := range config.MavenRepositories {
+		repo = expandTilde(repo)
+		if _, err := os.Stat(repo); os.IsNotExist(err) {
+			log.Printf("Maven repository does not exist: %s", repo)
+			continue
+		}
+
+		deps, err := repoReader.ReadTree(repo)
+		if err != nil {
+			log.Printf("could not read dependencies with %s: %v", repoReader.Name(), err)
+			report.addReaderError(repo, repoReader.Name(), err)
+			continue
+		}
+		log.Printf("Scanned Maven repository %s: %d cached artifacts", repo, len(deps))
+		results = append(results, findMatches(deps, badPackages, repo)...)
+	}
+
 	// persist scan state
 	if scanStatePath != "" {
 		if err := statepkg.SaveScanState(scanStatePath, state); err != nil {
@@ -379,69 +568,177 @@ func runScan(config *Config, forceRescan bool) {
 
 	duration := time.Since(startTime)
 	log.Printf("Scan completed in %s. Files scanned: %d", duration, filesScanned)
+	report.FilesScanned = filesScanned
+
+	for i := range results {
+		results[i].ListSignature = lists.signatureOf(results[i].ListPath)
+	}
 
 	if len(results) > 0 {
 		log.Printf("⚠️  WARNING: Found %d infected dependencies!", len(results))
 		for _, result := range results {
-			log.Printf("  - %s@%s in %s (matched: %s)", result.Package, result.Version, result.File, result.List)
+			log.Printf("  - %s@%s in %s%s (matched: %s)", result.Package, result.Version, result.File, formatLocation(result.Location), formatMatch(result))
+			for _, detail := range resultDetails(result) {
+				log.Printf("      %s", detail)
+			}
 		}
 
 		// Show desktop notification
-		message := fmt.Sprintf("Found %d infected dependencies! Check logs for details.", len(results))
-		beeep.Alert("Dewormer - Threats Detected", message, "")
+		if opts.Notify {
+			beeep.Alert("Dewormer - Threats Detected", notificationMessage(results), "")
+		}
 	} else {
 		log.Println("✓ No threats detected")
 	}
+
+	report.finish(results)
+	return report
 }
 
-func loadBadPackages(listPaths []string) map[string]map[string]string {
-	// Map of package@version -> list filename
-	badPackages := make(map[string]map[string]string)
+// loadedLists are the bad package lists loaded for a run.
+type loadedLists struct {
+	set *badlists.Set
+	// paths are the lists that were loaded, including ones that failed.
+	paths []string
+	// signatures is the signature status of each list by cleaned path.
+	signatures map[string]signature.Status
+	policy     signature.Policy
+	// advisoryDB is the GitHub Advisory Database among paths, if any.
+	advisoryDB string
+	// errs are the lists that could not be read or were refused.
+	errs []badlists.ListError
+}
 
-	for _, listPath := range listPaths {
-		file, err := os.Open(listPath)
-		if err != nil {
-			log.Printf("Could not open bad package list %s: %v", listPath, err)
-			continue
-		}
-		defer file.Close()
+// signatureOf returns the signature status of the list at listPath.
+func (l loadedLists) signatureOf(listPath string) signature.Status {
+	return l.signatures[filepath.Clean(listPath)]
+}
 
-		listName := filepath.Base(listPath)
-		scanner := bufio.NewScanner(file)
+// loadLists refreshes the remote lists and loads the configured lists, the
+// lists directory and the GitHub Advisory Database, subject to the
+// signature policy.
+func loadLists(config *Config) loadedLists {
+	// Build the list of bad package lists to load.
+	// We use any entries in config.BadPackageLists plus every file and
+	// directory found in ~/.dewormer/bad_package_lists so users don't need to
+	// enumerate each file.
+	listPaths := make([]string, 0, len(config.BadPackageLists))
+	// add configured lists first (may be empty)
+	listPaths = append(listPaths, config.BadPackageLists...)
 
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
+	// also include every file under ~/.dewormer/bad_package_lists (or an
+	// override directory supplied by --bad-package-files).
+	listsDir := ""
+	if BadListsDirOverride != "" {
+		listsDir = BadListsDirOverride
+	} else if home, err := os.UserHomeDir(); err == nil {
+		listsDir = filepath.Join(home, ".dewormer", "bad_package_lists")
+	}
 
-			// Skip comments and empty lines
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
+	verifier, policy := signatureSettings(config)
 
-			// Expected format: package@version
-			parts := strings.Split(line, "@")
-			if len(parts) < 2 {
-				continue
-			}
+	// download remote lists into the lists directory so they are picked up
+	// below like any other list
+	if len(config.RemoteLists) > 0 && listsDir != "" {
+		remoteStatePath := filepath.Join(filepath.Dir(getConfigPath()), "remote_lists.json")
+		fetcher := remote.NewFetcher(listsDir, remoteStatePath)
+		if policy != signature.PolicyOff {
+			fetcher.Verifier = verifier
+		}
+		fetcher.Refresh(config.RemoteLists)
+	}
 
-			pkg := strings.Join(parts[:len(parts)-1], "@") // Handle scoped packages like @rxap/ngx-bootstrap
-			version := parts[len(parts)-1]
+	if listsDir != "" {
+		if entries, err := os.ReadDir(listsDir); err == nil {
+			for _, e := range entries {
+				// directories hold OSV advisories; skip hidden files and
+				// directories (.DS_Store, .git, partial downloads) and the
+				// signatures of lists
+				if strings.HasPrefix(e.Name(), ".") || signature.IsSignatureFile(e.Name()) {
+					continue
+				}
+				full := filepath.Join(listsDir, e.Name())
+				// avoid duplicates
+				found := false
 
-			if badPackages[pkg] == nil {
-				badPackages[pkg] = make(map[string]string)
+				for _, p := range listPaths {
+					if p == full {
+						found = true
+						break
+					}
+				}
+				if !found {
+					listPaths = append(listPaths, full)
+				}
 			}
-			badPackages[pkg][version] = listName
 		}
 	}
 
-	return badPackages
+	listPaths, listData, listSignatures, errs := verifyLists(listPaths, verifier, policy)
+
+	// Load all bad packages; signed lists are loaded from the bytes that
+	// were verified, so a list replaced after the check isn't trusted
+	badPackages, loadErrs := badlists.LoadData(listPaths, listData)
+	errs = append(errs, loadErrs...)
+	var advisoryDB string
+	if config.GitHubAdvisoryDatabase != "" && policy == signature.PolicyRequire {
+		log.Printf("Refusing GitHub Advisory Database %s: directories can't be signed", config.GitHubAdvisoryDatabase)
+		errs = append(errs, badlists.ListError{Path: config.GitHubAdvisoryDatabase, Err: errors.New("directories can't be signed")})
+	} else if config.GitHubAdvisoryDatabase != "" {
+		advisoryDB = expandTilde(config.GitHubAdvisoryDatabase)
+		if policy == signature.PolicyWarn {
+			log.Printf("Warning: GitHub Advisory Database %s is not signed", advisoryDB)
+			listSignatures[filepath.Clean(advisoryDB)] = signature.StatusUnsigned
+		}
+		n, err := badlists.LoadGitHubAdvisories(badPackages, advisoryDB, config.GitHubAdvisoryMinSeverity)
+		if err != nil {
+			log.Printf("Could not load GitHub advisories from %s: %v", advisoryDB, err)
+			errs = append(errs, badlists.ListError{Path: advisoryDB, Err: err})
+		} else {
+			log.Printf("Loaded %d GitHub advisories from %s", n, advisoryDB)
+		}
+		// changes to the database should trigger rescans like list changes
+		listPaths = append(listPaths, advisoryDB)
+	}
+	log.Printf("Loaded %d bad packages from %d lists", badPackages.Len(), len(listPaths))
+
+	return loadedLists{
+		set:        badPackages,
+		paths:      listPaths,
+		signatures: listSignatures,
+		policy:     policy,
+		advisoryDB: advisoryDB,
+		errs:       errs,
+	}
+}
+
+// listsFingerprint identifies the loaded bad package lists by the path,
+// modification time and size of each, together with the settings that
+// change what is loaded from them.
+func listsFingerprint(paths []string, settings ...string) string {
+	h := sha256.New()
+	for _, p := range paths {
+		var mod, size int64
+		if info, err := os.Stat(p); err == nil {
+			mod, size = info.ModTime().UnixNano(), info.Size()
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", p, mod, size)
+	}
+	for _, s := range settings {
+		fmt.Fprintf(h, "%s\n", s)
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // shouldScan determines whether a given file should be scanned based on the
-// persisted state (map of abs path -> unix-nano timestamp), the file's
+// persisted state (map of abs path -> last scan record), the file's
 // modification time and the latest modification time among bad-package lists.
+// Records that are legacy, from an older format, made against other lists
+// (see listsFingerprint) or whose sources have changed since are always
+// rescanned.
 // It returns the normalized absolute path, the lastScan time (zero if never)
 // and whether a scan is required.
-func shouldScan(path string, info os.FileInfo, latestListMod time.Time, state map[string]int64, forceRescan bool) (string, time.Time, bool) {
+func shouldScan(path string, info os.FileInfo, latestListMod time.Time, fingerprint string, state statepkg.ScanState, forceRescan bool) (string, time.Time, bool) {
 	abs := path
 	if !filepath.IsAbs(abs) {
 		if a, err := filepath.Abs(path); err == nil {
@@ -452,10 +749,11 @@ func shouldScan(path string, info os.FileInfo, latestListMod time.Time, state ma
 
 	pkgMod := info.ModTime()
 	var lastScan time.Time
-	if ts, ok := state[abs]; ok && ts > 0 {
-		lastScan = time.Unix(0, ts)
+	fs, ok := state[abs]
+	if ok && fs.ScannedAt > 0 {
+		lastScan = time.Unix(0, fs.ScannedAt)
 	}
-	if forceRescan {
+	if forceRescan || !fs.Current(fingerprint) || fs.SourcesChanged() {
 		return abs, lastScan, true
 	}
 
@@ -463,21 +761,253 @@ func shouldScan(path string, info os.FileInfo, latestListMod time.Time, state ma
 	return abs, lastScan, need
 }
 
-func findMatches(deps map[string]string, badPackages map[string]map[string]string, filePath string) []ScanResult {
+func findMatches(deps []readers.Dependency, badPackages *badlists.Set, filePath string) []ScanResult {
 	var results []ScanResult
 
-	for pkg, version := range deps {
-		if badVersions, exists := badPackages[pkg]; exists {
-			if listName, isBad := badVersions[version]; isBad {
-				results = append(results, ScanResult{
-					Package: pkg,
-					Version: version,
-					File:    filePath,
-					List:    listName,
-				})
-			}
+	for _, dep := range deps {
+		for _, entry := range badPackages.Match(dep) {
+			results = append(results, newResult(dep, entry, filePath))
 		}
 	}
 
 	return results
 }
+
+// newResult describes a dependency in filePath matched by a list entry.
+func newResult(dep readers.Dependency, entry badlists.Entry, filePath string) ScanResult {
+	return ScanResult{
+		Package:    dep.Name,
+		Version:    dep.Version,
+		Ecosystem:  dep.Ecosystem,
+		File:       filePath,
+		Location:   dep.Location,
+		Line:       dep.Line,
+		List:       entry.List,
+		ListPath:   entry.ListPath,
+		Advisory:   entry.Advisory,
+		Summary:    entry.Summary,
+		Severity:   entry.Severity,
+		Reason:     entry.Reason,
+		References: entry.References,
+		Added:      entry.Added,
+	}
+}
+
+// findingsForState converts scan results into the form persisted in the scan
+// state file.
+func findingsForState(results []ScanResult) []statepkg.Finding {
+	findings := make([]statepkg.Finding, 0, len(results))
+	for _, r := range results {
+		findings = append(findings, statepkg.Finding{
+			Package:    r.Package,
+			Version:    r.Version,
+			Ecosystem:  r.Ecosystem,
+			Location:   r.Location,
+			Line:       r.Line,
+			List:       r.List,
+			ListPath:   r.ListPath,
+			Advisory:   r.Advisory,
+			Summary:    r.Summary,
+			Severity:   r.Severity,
+			Reason:     r.Reason,
+			References: r.References,
+			Added:      r.Added,
+		})
+	}
+	return findings
+}
+
+// resultsFromState rebuilds the scan results recorded for filePath during a
+// previous scan.
+func resultsFromState(filePath string, fs statepkg.FileState) []ScanResult {
+	var results []ScanResult
+	for _, f := range fs.Findings {
+		results = append(results, ScanResult{
+			Package:    f.Package,
+			Version:    f.Version,
+			Ecosystem:  f.Ecosystem,
+			File:       filePath,
+			Location:   f.Location,
+			Line:       f.Line,
+			List:       f.List,
+			ListPath:   f.ListPath,
+			Advisory:   f.Advisory,
+			Summary:    f.Summary,
+			Severity:   f.Severity,
+			Reason:     f.Reason,
+			References: f.References,
+			Added:      f.Added,
+		})
+	}
+	return results
+}
+
+// formatLocation renders a result location as a log suffix, or "" when the
+// reader did not provide one.
+func formatLocation(location string) string {
+	if location == "" {
+		return ""
+	}
+	return " [" + location + "]"
+}
+
+// formatMatch describes what a result was matched by: the list, and the
+// advisory and severity when known.
+func formatMatch(result ScanResult) string {
+	match := result.List
+	if result.ListSignature != "" {
+		match += " (signature " + string(result.ListSignature) + ")"
+	}
+	switch {
+	case result.Advisory != "" && result.Summary != "":
+		match += ", " + result.Advisory + ": " + result.Summary
+	case result.Advisory != "":
+		match += ", " + result.Advisory
+	}
+	if result.Severity != "" {
+		match += ", severity: " + result.Severity
+	}
+	return match
+}
+
+// signatureSettings parses the trusted keys and signature policy. Invalid
+// keys are logged and ignored; an unknown policy is treated as require so a
+// typo doesn't silently disable verification.
+func signatureSettings(config *Config) (*signature.Verifier, signature.Policy) {
+	var keys []signature.PublicKey
+	for i, s := range config.TrustedKeys {
+		k, err := signature.ParsePublicKey(s)
+		if err != nil {
+			log.Printf("Ignoring trusted key %d: %v", i+1, err)
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	policy, err := signature.ParsePolicy(config.SignaturePolicy, len(keys) > 0)
+	if err != nil {
+		log.Printf("%v; requiring signatures", err)
+		policy = signature.PolicyRequire
+	}
+	if policy == signature.PolicyRequire && len(keys) == 0 {
+		log.Println("Warning: signature_policy is require but no trusted_keys are configured; every list will be refused")
+	}
+	return signature.NewVerifier(keys), policy
+}
+
+// verifyLists checks the signature of every list according to policy. It
+// returns the lists to load, the contents read for the check by path, the
+// status of each list by cleaned path, and the lists refused under require.
+func verifyLists(listPaths []string, verifier *signature.Verifier, policy signature.Policy) ([]string, map[string][]byte, map[string]signature.Status, []badlists.ListError) {
+	data := make(map[string][]byte)
+	statuses := make(map[string]signature.Status)
+	if policy == signature.PolicyOff {
+		return listPaths, data, statuses, nil
+	}
+
+	var kept []string
+	var refused []badlists.ListError
+	for _, p := range listPaths {
+		status, contents, err := verifier.ReadVerified(p)
+		statuses[filepath.Clean(p)] = status
+
+		switch {
+		case status == signature.StatusVerified:
+			log.Printf("List %s: signature verified", p)
+		case policy == signature.PolicyRequire && status == signature.StatusUnsigned:
+			log.Printf("Refusing unsigned list %s", p)
+			refused = append(refused, badlists.ListError{Path: p, Err: errors.New("not signed")})
+			continue
+		case policy == signature.PolicyRequire:
+			log.Printf("Refusing list %s: invalid signature: %v", p, err)
+			refused = append(refused, badlists.ListError{Path: p, Err: fmt.Errorf("invalid signature: %w", err)})
+			continue
+		case status == signature.StatusUnsigned:
+			log.Printf("Warning: list %s is not signed", p)
+		default:
+			log.Printf("Warning: list %s has an invalid signature: %v", p, err)
+		}
+		if contents != nil {
+			data[p] = contents
+		}
+		kept = append(kept, p)
+	}
+	return kept, data, statuses, refused
+}
+
+// resultDetails returns the reason, date added and references of a result's
+// list entry as log lines.
+func resultDetails(result ScanResult) []string {
+	var details []string
+	if result.Reason != "" {
+		details = append(details, "reason: "+result.Reason)
+	}
+	if result.Added != "" {
+		details = append(details, "added: "+result.Added)
+	}
+	for _, ref := range result.References {
+		details = append(details, "see: "+ref)
+	}
+	return details
+}
+
+// notificationMessage summarizes the results for the desktop notification,
+// naming the most severe finding when the lists describe it.
+func notificationMessage(results []ScanResult) string {
+	worst := results[0]
+	for _, r := range results[1:] {
+		if badlists.SeverityRank(r.Severity) > badlists.SeverityRank(worst.Severity) {
+			worst = r
+		}
+	}
+
+	reason := worst.Reason
+	if reason == "" {
+		reason = worst.Summary
+	}
+	if worst.Severity == "" && reason == "" {
+		return fmt.Sprintf("Found %d infected dependencies! Check logs for details.", len(results))
+	}
+
+	detail := worst.Package + "@" + worst.Version
+	if worst.Severity != "" {
+		detail += " (" + worst.Severity + ")"
+	}
+	if reason != "" {
+		detail += ": " + reason
+	}
+	return fmt.Sprintf("Found %d infected dependencies! %s. Check logs for details.", len(results), strings.TrimSuffix(detail, "."))
+}
+
+// mergeInstalled adds findings from installed node_modules packages to the
+// lockfile results. An installed package that a lockfile in the same project
+// directory already reported (same name and version) is the same install and
+// is not reported twice. When the lockfile records install paths
+// (package-lock.json), the install path must match too, so the same version
+// installed a second time elsewhere in node_modules is still reported.
+func mergeInstalled(results, installed []ScanResult) []ScanResult {
+	reported := make(map[string]bool, len(results))
+	for _, r := range results {
+		key := filepath.Dir(r.File) + "\x00" + r.Package + "@" + r.Version
+		if isInstallPath(r.Location) {
+			key += "\x00" + r.Location
+		}
+		reported[key] = true
+	}
+
+	for _, r := range installed {
+		// installed results use the project directory as File
+		key := r.File + "\x00" + r.Package + "@" + r.Version
+		if reported[key] || reported[key+"\x00"+r.Location] {
+			continue
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// isInstallPath reports whether a location is a node_modules install path
+// like those of package-lock.json and the node_modules reader.
+func isInstallPath(location string) bool {
+	return strings.HasPrefix(location, "node_modules/")
+}
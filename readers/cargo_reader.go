@@ -0,0 +1,20 @@
+package readers
+
+// CargoLockReader reads Rust Cargo.lock files.
+type CargoLockReader struct{}
+
+func NewCargoLockReader() DependencyReader { return &CargoLockReader{} }
+
+func (r *CargoLockReader) Name() string { return "Cargo.lock" }
+
+func (r *CargoLockReader) Supports(filename string) bool {
+	return filename == "Cargo.lock"
+}
+
+// ReadDependencies reports every [[package]] entry that comes from a registry
+// or git source; workspace crates have no source and are skipped.
+func (r *CargoLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	return readTOMLPackages(path, EcosystemCargo, nil, func(pkg map[string]any) bool {
+		return tomlString(pkg, "source") != ""
+	})
+}
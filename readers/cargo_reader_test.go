@@ -0,0 +1,54 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCargoLockReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "Cargo.lock")
+	data := `# This file is automatically @generated by Cargo.
+# It is not intended for manual editing.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "serde",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.188"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "cf9e0fcba69a370eed61bcf2b728575f726b50b55cba78064753d708ddc7549e"
+
+[[package]]
+name = "rand_core"
+version = "0.6.4"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write Cargo.lock: %v", err)
+	}
+
+	deps, err := NewCargoLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := versionOf(deps, "serde"); got != "1.0.188" {
+		t.Fatalf("expected serde=1.0.188 got=%q", got)
+	}
+	if got := versionOf(deps, "rand_core"); got != "0.6.4" {
+		t.Fatalf("expected crate names to be kept as-is, got rand_core=%q", got)
+	}
+	if got := versionOf(deps, "app"); got != "" {
+		t.Fatalf("workspace crates must not be reported")
+	}
+	if deps[0].Ecosystem != EcosystemCargo {
+		t.Fatalf("expected cargo ecosystem, got %q", deps[0].Ecosystem)
+	}
+}
@@ -0,0 +1,67 @@
+package readers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ComposerLockReader reads PHP composer.lock files.
+type ComposerLockReader struct{}
+
+func NewComposerLockReader() DependencyReader { return &ComposerLockReader{} }
+
+func (r *ComposerLockReader) Name() string { return "composer.lock" }
+
+func (r *ComposerLockReader) Supports(filename string) bool {
+	return filename == "composer.lock"
+}
+
+type composerLock struct {
+	Packages    []composerPackage `json:"packages"`
+	PackagesDev []composerPackage `json:"packages-dev"`
+}
+
+type composerPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ReadDependencies reports "packages" and "packages-dev". Tag versions such
+// as v5.4.0 are reported without the "v", which Composer treats as equal.
+func (r *ComposerLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var cl composerLock
+	if err := json.Unmarshal(data, &cl); err != nil {
+		return nil, fmt.Errorf("unmarshal composer.lock: %w", err)
+	}
+
+	var deps []Dependency
+	for _, section := range []struct {
+		name     string
+		packages []composerPackage
+	}{{"packages", cl.Packages}, {"packages-dev", cl.PackagesDev}} {
+		for _, p := range section.packages {
+			if p.Name == "" || p.Version == "" {
+				continue
+			}
+			version := p.Version
+			if len(version) > 1 && version[0] == 'v' && version[1] >= '0' && version[1] <= '9' {
+				version = strings.TrimPrefix(version, "v")
+			}
+			deps = append(deps, Dependency{
+				Name:      p.Name,
+				Version:   version,
+				Ecosystem: EcosystemComposer,
+				Location:  section.name,
+			})
+		}
+	}
+
+	return deps, nil
+}
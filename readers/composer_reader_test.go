@@ -0,0 +1,45 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComposerLockReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "composer.lock")
+	data := `{
+  "content-hash": "abc",
+  "packages": [
+    { "name": "symfony/console", "version": "v5.4.0" },
+    { "name": "monolog/monolog", "version": "2.9.1" }
+  ],
+  "packages-dev": [
+    { "name": "phpunit/phpunit", "version": "9.6.13" },
+    { "name": "org/dev-branch", "version": "dev-main" }
+  ]
+}`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write composer.lock: %v", err)
+	}
+
+	deps, err := NewComposerLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"symfony/console": "5.4.0",
+		"monolog/monolog": "2.9.1",
+		"phpunit/phpunit": "9.6.13",
+		"org/dev-branch":  "dev-main",
+	}
+	for name, version := range want {
+		if got := versionOf(deps, name); got != version {
+			t.Fatalf("expected %s=%s got=%q", name, version, got)
+		}
+	}
+	if d := occurrences(deps, "phpunit/phpunit"); d[0].Location != "packages-dev" {
+		t.Fatalf("expected packages-dev location, got %q", d[0].Location)
+	}
+}
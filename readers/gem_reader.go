@@ -0,0 +1,78 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GemfileLockReader reads Ruby Gemfile.lock files.
+type GemfileLockReader struct{}
+
+func NewGemfileLockReader() DependencyReader { return &GemfileLockReader{} }
+
+func (r *GemfileLockReader) Name() string { return "Gemfile.lock" }
+
+func (r *GemfileLockReader) Supports(filename string) bool {
+	return filename == "Gemfile.lock"
+}
+
+// ReadDependencies reports the gems listed under the "specs:" of the GEM
+// section:
+//
+//	GEM
+//	  remote: https://rubygems.org/
+//	  specs:
+//	    nokogiri (1.13.10-x86_64-linux)
+//	      racc (~> 1.4)
+//
+// Gems are indented by four spaces; the deeper lines are their requirements.
+// Platform suffixes are stripped from the version (gem versions never contain
+// a dash).
+func (r *GemfileLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var deps []Dependency
+	section := ""
+	inSpecs := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case indent == 0:
+			section, inSpecs = line, false
+		case indent == 2:
+			inSpecs = section == "GEM" && strings.TrimSpace(line) == "specs:"
+		case indent == 4 && inSpecs:
+			name, rest, ok := strings.Cut(strings.TrimSpace(line), " (")
+			if !ok {
+				continue
+			}
+			version, _, _ := strings.Cut(strings.TrimSuffix(rest, ")"), "-")
+			deps = append(deps, Dependency{
+				Name:      name,
+				Version:   version,
+				Ecosystem: EcosystemGem,
+				Location:  "GEM specs",
+				Line:      lineNo,
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read Gemfile.lock: %w", err)
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,61 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGemfileLockReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "Gemfile.lock")
+	data := `GIT
+  remote: https://github.com/org/forked.git
+  revision: 0123abc
+  specs:
+    forked (0.1.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    actionpack (7.0.4)
+      actionview (= 7.0.4)
+    nokogiri (1.13.10-x86_64-linux)
+      racc (~> 1.4)
+    rest-client (1.6.13)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  actionpack
+  nokogiri
+
+BUNDLED WITH
+   2.4.10
+`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write Gemfile.lock: %v", err)
+	}
+
+	deps, err := NewGemfileLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"actionpack":  "7.0.4",
+		"nokogiri":    "1.13.10",
+		"rest-client": "1.6.13",
+	}
+	for name, version := range want {
+		if got := versionOf(deps, name); got != version {
+			t.Fatalf("expected %s=%s got=%q", name, version, got)
+		}
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("expected %d gems, got %d: %+v", len(want), len(deps), deps)
+	}
+	if got := lineOf(deps, "rest-client"); got != 14 {
+		t.Fatalf("expected rest-client on line 14, got %d", got)
+	}
+}
@@ -0,0 +1,201 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GoSumReader reads go.sum files, which list every module version in the
+// build graph. Dependencies are named by module path (github.com/org/mod)
+// with the version as written, including the leading "v".
+type GoSumReader struct{}
+
+func NewGoSumReader() DependencyReader { return &GoSumReader{} }
+
+func (r *GoSumReader) Name() string { return "go.sum" }
+
+func (r *GoSumReader) Supports(filename string) bool {
+	return filename == "go.sum"
+}
+
+func (r *GoSumReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	// most modules have both a "v1.2.3" and a "v1.2.3/go.mod" hash line
+	seen := make(map[string]bool)
+	var deps []Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		module, version := fields[0], strings.TrimSuffix(fields[1], "/go.mod")
+		if seen[module+"@"+version] {
+			continue
+		}
+		seen[module+"@"+version] = true
+
+		deps = append(deps, Dependency{
+			Name:      module,
+			Version:   version,
+			Ecosystem: EcosystemGo,
+			Location:  "go.sum",
+			Line:      lineNo,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read go.sum: %w", err)
+	}
+
+	return deps, nil
+}
+
+// GoModReader reads go.mod files. Required modules are reported after
+// applying replace directives; modules replaced by a local directory are not
+// reported since no published version is used.
+type GoModReader struct{}
+
+func NewGoModReader() DependencyReader { return &GoModReader{} }
+
+func (r *GoModReader) Name() string { return "go.mod" }
+
+func (r *GoModReader) Supports(filename string) bool {
+	return filename == "go.mod"
+}
+
+// goModReplace is a replace directive. oldVersion is empty when every
+// version of oldPath is replaced, newVersion when the target is a directory.
+type goModReplace struct {
+	oldPath, oldVersion string
+	newPath, newVersion string
+}
+
+// goModRequire is a require directive and the line it is on.
+type goModRequire struct {
+	path, version string
+	line          int
+}
+
+func (r *GoModReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var requires []goModRequire
+	var replaces []goModReplace
+
+	block := ""
+	lineNo := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		verb := block
+		if block == "" {
+			verb, fields = fields[0], fields[1:]
+			if len(fields) == 1 && fields[0] == "(" {
+				block = verb
+				continue
+			}
+		} else if fields[0] == ")" {
+			block = ""
+			continue
+		}
+
+		for i := range fields {
+			fields[i] = strings.Trim(fields[i], `"`+"`")
+		}
+		switch verb {
+		case "require":
+			if len(fields) >= 2 {
+				requires = append(requires, goModRequire{fields[0], fields[1], lineNo})
+			}
+		case "replace":
+			if rep, ok := parseGoModReplace(fields); ok {
+				replaces = append(replaces, rep)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read go.mod: %w", err)
+	}
+
+	var deps []Dependency
+	for _, req := range requires {
+		name, version, location := req.path, req.version, "require"
+		if rep := findGoModReplace(replaces, req); rep != nil {
+			name, version, location = rep.newPath, rep.newVersion, "replace"
+		}
+		if version == "" {
+			continue // replaced by a local directory
+		}
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   version,
+			Ecosystem: EcosystemGo,
+			Location:  location,
+			Line:      req.line,
+		})
+	}
+
+	return deps, nil
+}
+
+// findGoModReplace returns the replace directive that applies to req. As in
+// Go, a replacement of the required version takes precedence over one of
+// every version, wherever either appears.
+func findGoModReplace(replaces []goModReplace, req goModRequire) *goModReplace {
+	var wildcard *goModReplace
+	for i, rep := range replaces {
+		if rep.oldPath != req.path {
+			continue
+		}
+		if rep.oldVersion == req.version {
+			return &replaces[i]
+		}
+		if rep.oldVersion == "" {
+			wildcard = &replaces[i]
+		}
+	}
+	return wildcard
+}
+
+// parseGoModReplace parses `old [v] => new [v]`.
+func parseGoModReplace(fields []string) (goModReplace, bool) {
+	arrow := -1
+	for i, f := range fields {
+		if f == "=>" {
+			arrow = i
+		}
+	}
+	if arrow < 1 || arrow > 2 || len(fields)-arrow-1 < 1 || len(fields)-arrow-1 > 2 {
+		return goModReplace{}, false
+	}
+
+	rep := goModReplace{oldPath: fields[0], newPath: fields[arrow+1]}
+	if arrow == 2 {
+		rep.oldVersion = fields[1]
+	}
+	if len(fields) == arrow+3 {
+		rep.newVersion = fields[arrow+2]
+	}
+	return rep, true
+}
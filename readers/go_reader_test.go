@@ -0,0 +1,90 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoSumReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "go.sum")
+	data := `github.com/evil/mod v1.2.3 h1:abc=
+github.com/evil/mod v1.2.3/go.mod h1:def=
+github.com/evil/mod v1.2.2/go.mod h1:ghi=
+golang.org/x/sys v0.30.0 h1:jkl=
+`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write go.sum: %v", err)
+	}
+
+	deps, err := NewGoSumReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := len(occurrences(deps, "github.com/evil/mod")); got != 2 {
+		t.Fatalf("expected v1.2.3 and v1.2.2 of github.com/evil/mod, got %d", got)
+	}
+	if got := versionOf(deps, "golang.org/x/sys"); got != "v0.30.0" {
+		t.Fatalf("expected golang.org/x/sys=v0.30.0 got=%q", got)
+	}
+	if got := lineOf(deps, "golang.org/x/sys"); got != 4 {
+		t.Fatalf("expected golang.org/x/sys on line 4, got %d", got)
+	}
+	if deps[0].Ecosystem != EcosystemGo {
+		t.Fatalf("expected golang ecosystem, got %q", deps[0].Ecosystem)
+	}
+}
+
+func TestGoModReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "go.mod")
+	data := `module github.com/example/app
+
+go 1.22
+
+require github.com/gen2brain/beeep v0.11.1
+
+require (
+	github.com/evil/mod v1.2.3 // indirect
+	github.com/forked/mod v0.1.0
+	github.com/local/mod v0.0.0
+	github.com/pinned/mod v1.0.0
+)
+
+replace github.com/forked/mod => github.com/example/mod-fork v0.1.1
+
+replace (
+	github.com/local/mod => ../local
+	github.com/evil/mod v1.0.0 => github.com/evil/mod v1.0.1
+	github.com/pinned/mod v1.0.0 => github.com/pinned/mod v1.0.1
+	github.com/pinned/mod => github.com/pinned/mod v9.9.9
+)
+`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+
+	deps, err := NewGoModReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"github.com/gen2brain/beeep":  "v0.11.1",
+		"github.com/evil/mod":         "v1.2.3", // replace only applies to v1.0.0
+		"github.com/example/mod-fork": "v0.1.1",
+		"github.com/pinned/mod":       "v1.0.1", // the versioned replace wins over the later wildcard
+	}
+	for name, version := range want {
+		if got := versionOf(deps, name); got != version {
+			t.Fatalf("expected %s=%s got=%q", name, version, got)
+		}
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("expected %d dependencies, got %d: %+v", len(want), len(deps), deps)
+	}
+	// a replaced module is reported on the line of its require
+	if got := lineOf(deps, "github.com/example/mod-fork"); got != 9 {
+		t.Fatalf("expected mod-fork on line 9, got %d", got)
+	}
+}
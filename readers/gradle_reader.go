@@ -0,0 +1,164 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// GradleLockfileReader reads Gradle dependency lockfiles (gradle.lockfile and
+// buildscript-gradle.lockfile). Dependencies are named groupId:artifactId so
+// entries in Maven bad lists apply to Gradle builds too.
+type GradleLockfileReader struct{}
+
+func NewGradleLockfileReader() DependencyReader { return &GradleLockfileReader{} }
+
+func (r *GradleLockfileReader) Name() string { return "gradle.lockfile" }
+
+func (r *GradleLockfileReader) Supports(filename string) bool {
+	return filename == "gradle.lockfile" || filename == "buildscript-gradle.lockfile"
+}
+
+// ReadDependencies parses lines of the form
+//
+//	com.google.guava:guava:31.1-jre=compileClasspath,runtimeClasspath
+//
+// The configurations are reported as the location.
+func (r *GradleLockfileReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var deps []Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		coords, configurations, _ := strings.Cut(line, "=")
+		parts := strings.Split(coords, ":")
+		if len(parts) != 3 {
+			continue // e.g. "empty=annotationProcessor"
+		}
+		deps = append(deps, Dependency{
+			Name:      parts[0] + ":" + parts[1],
+			Version:   parts[2],
+			Ecosystem: EcosystemMaven,
+			Location:  configurations,
+			Line:      lineNo,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read gradle.lockfile: %w", err)
+	}
+
+	return deps, nil
+}
+
+// GradleVersionCatalogReader reads Gradle version catalogs
+// (gradle/libs.versions.toml).
+type GradleVersionCatalogReader struct{}
+
+func NewGradleVersionCatalogReader() DependencyReader { return &GradleVersionCatalogReader{} }
+
+func (r *GradleVersionCatalogReader) Name() string { return "libs.versions.toml" }
+
+func (r *GradleVersionCatalogReader) Supports(filename string) bool {
+	return filename == "libs.versions.toml"
+}
+
+// ReadDependencies reports every [libraries] entry with a resolvable version.
+// Libraries may be declared as "group:artifact:version" strings or as tables
+// using module or group/name, with version given literally, as a rich
+// version table (strictly/require/prefer) or as version.ref into [versions].
+func (r *GradleVersionCatalogReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	doc, err := parseTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse version catalog: %w", err)
+	}
+
+	versions, _ := doc["versions"].(map[string]any)
+	libraries, _ := doc["libraries"].(map[string]any)
+
+	aliases := make([]string, 0, len(libraries))
+	for alias := range libraries {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	var deps []Dependency
+	for _, alias := range aliases {
+		name, version := catalogLibrary(libraries[alias], versions)
+		if name == "" || version == "" {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   version,
+			Ecosystem: EcosystemMaven,
+			Location:  "libraries." + alias,
+		})
+	}
+
+	return deps, nil
+}
+
+// catalogLibrary returns groupId:artifactId and the version of a library.
+func catalogLibrary(lib any, versions map[string]any) (string, string) {
+	switch l := lib.(type) {
+	case string:
+		parts := strings.Split(l, ":")
+		if len(parts) != 3 {
+			return "", ""
+		}
+		return parts[0] + ":" + parts[1], parts[2]
+	case map[string]any:
+		name := tomlString(l, "module")
+		if name == "" && tomlString(l, "group") != "" && tomlString(l, "name") != "" {
+			name = tomlString(l, "group") + ":" + tomlString(l, "name")
+		}
+
+		var version string
+		switch v := l["version"].(type) {
+		case string:
+			version = v
+		case map[string]any:
+			if ref := tomlString(v, "ref"); ref != "" {
+				version = catalogVersion(versions[ref])
+			} else {
+				version = catalogVersion(v)
+			}
+		}
+		return name, version
+	}
+	return "", ""
+}
+
+// catalogVersion resolves a [versions] value, which is either a plain string
+// or a rich version table.
+func catalogVersion(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		for _, key := range []string{"strictly", "require", "prefer"} {
+			if s := tomlString(v, key); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
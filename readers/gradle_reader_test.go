@@ -0,0 +1,88 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGradleLockfileReader_ReadDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	fpath := filepath.Join(tmpDir, "gradle.lockfile")
+
+	data := `# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:guava:31.1-jre=compileClasspath,runtimeClasspath
+org.apache.logging.log4j:log4j-core:2.14.1=runtimeClasspath
+empty=annotationProcessor
+`
+
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write tmp gradle.lockfile: %v", err)
+	}
+
+	r := NewGradleLockfileReader()
+	if !r.Supports("buildscript-gradle.lockfile") {
+		t.Fatalf("expected reader to support buildscript-gradle.lockfile")
+	}
+	deps, err := r.ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := versionOf(deps, "com.google.guava:guava"); got != "31.1-jre" {
+		t.Fatalf("expected com.google.guava:guava=31.1-jre got=%q", got)
+	}
+	if got := versionOf(deps, "org.apache.logging.log4j:log4j-core"); got != "2.14.1" {
+		t.Fatalf("expected log4j-core=2.14.1 got=%q", got)
+	}
+	if got := lineOf(deps, "org.apache.logging.log4j:log4j-core"); got != 5 {
+		t.Fatalf("expected log4j-core on line 5, got %d", got)
+	}
+	if len(deps) != 2 {
+		t.Fatalf("expected 2 dependencies, got %d", len(deps))
+	}
+}
+
+func TestGradleVersionCatalogReader_ReadDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	fpath := filepath.Join(tmpDir, "libs.versions.toml")
+
+	data := `[versions]
+log4j = "2.14.1"
+guava = { strictly = "31.1-jre" }
+
+[libraries]
+log4j-core = { module = "org.apache.logging.log4j:log4j-core", version.ref = "log4j" }
+guava = { group = "com.google.guava", name = "guava", version.ref = "guava" }
+evil = "com.example:evil:1.2.3"
+unversioned = { module = "com.example:bom-managed" }
+
+[plugins]
+kotlin = { id = "org.jetbrains.kotlin.jvm", version = "1.9.0" }
+`
+
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write tmp libs.versions.toml: %v", err)
+	}
+
+	deps, err := NewGradleVersionCatalogReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"org.apache.logging.log4j:log4j-core": "2.14.1",
+		"com.google.guava:guava":              "31.1-jre",
+		"com.example:evil":                    "1.2.3",
+	}
+	for name, version := range want {
+		if got := versionOf(deps, name); got != version {
+			t.Fatalf("expected %s=%s got=%q", name, version, got)
+		}
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("expected %d dependencies, got %d: %+v", len(want), len(deps), deps)
+	}
+}
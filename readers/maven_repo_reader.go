@@ -0,0 +1,74 @@
+package readers
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MavenRepositoryReader reports every artifact cached in a local Maven
+// repository (~/.m2/repository), which is where transitive dependencies end
+// up even though no pom in the project names them.
+type MavenRepositoryReader struct{}
+
+func NewMavenRepositoryReader() TreeReader { return &MavenRepositoryReader{} }
+
+func (r *MavenRepositoryReader) Name() string { return "maven-repository" }
+
+// ReadTree recognises the groupId/artifactId/version/ layout: a directory is
+// an artifact version when it contains <artifactId>-<version>.pom or .jar,
+// where artifactId and version are the names of its parent and itself.
+func (r *MavenRepositoryReader) ReadTree(root string) ([]Dependency, error) {
+	if st, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("stat repository: %w", err)
+	} else if !st.IsDir() {
+		return nil, fmt.Errorf("repository %s is not a directory", root)
+	}
+
+	var deps []Dependency
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip directories we can't access
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+
+		version := d.Name()
+		artifactDir := filepath.Dir(path)
+		artifactID := filepath.Base(artifactDir)
+		if !isMavenArtifactDir(path, artifactID, version) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filepath.Dir(artifactDir))
+		if err != nil || rel == "." {
+			return nil
+		}
+		deps = append(deps, Dependency{
+			Name:      strings.ReplaceAll(filepath.ToSlash(rel), "/", ".") + ":" + artifactID,
+			Version:   version,
+			Ecosystem: EcosystemMaven,
+			Location:  filepath.ToSlash(filepath.Join(rel, artifactID, version)),
+		})
+
+		// version directories don't contain further artifacts
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+func isMavenArtifactDir(dir, artifactID, version string) bool {
+	for _, ext := range []string{".pom", ".jar"} {
+		if _, err := os.Stat(filepath.Join(dir, artifactID+"-"+version+ext)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMavenRepositoryReader_ReadTree(t *testing.T) {
+	root := t.TempDir()
+
+	artifacts := []string{
+		"org/apache/logging/log4j/log4j-core/2.14.1/log4j-core-2.14.1.pom",
+		"org/apache/logging/log4j/log4j-core/2.17.1/log4j-core-2.17.1.jar",
+		"com/example/evil/1.2.3/evil-1.2.3.pom",
+		// metadata only, not a cached artifact version
+		"com/example/other/maven-metadata-central.xml",
+	}
+	for _, a := range artifacts {
+		p := filepath.Join(root, filepath.FromSlash(a))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte{}, 0644); err != nil {
+			t.Fatalf("write %s: %v", a, err)
+		}
+	}
+
+	deps, err := NewMavenRepositoryReader().ReadTree(root)
+	if err != nil {
+		t.Fatalf("ReadTree returned error: %v", err)
+	}
+
+	if got := len(occurrences(deps, "org.apache.logging.log4j:log4j-core")); got != 2 {
+		t.Fatalf("expected 2 cached log4j-core versions, got %d", got)
+	}
+	evil := occurrences(deps, "com.example:evil")
+	if len(evil) != 1 || evil[0].Version != "1.2.3" || evil[0].Location != "com/example/evil/1.2.3" {
+		t.Fatalf("unexpected com.example:evil occurrences: %+v", evil)
+	}
+	if len(deps) != 3 {
+		t.Fatalf("expected 3 artifacts, got %d: %+v", len(deps), deps)
+	}
+}
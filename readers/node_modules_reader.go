@@ -0,0 +1,85 @@
+package readers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NodeModulesReader reports the packages actually installed in a project's
+// node_modules directory by reading each package's package.json. Unlike a
+// lockfile this cannot be out of date with what executes.
+type NodeModulesReader struct{}
+
+func NewNodeModulesReader() TreeReader { return &NodeModulesReader{} }
+
+func (r *NodeModulesReader) Name() string { return "node_modules" }
+
+type packageManifest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ReadTree reads root/node_modules, where root is the project directory.
+// Locations are install paths relative to root, like the package-lock.json
+// "packages" keys (node_modules/a/node_modules/b). Symlinks are not
+// followed, so pnpm's store under node_modules/.pnpm is read rather than its
+// top-level links.
+func (r *NodeModulesReader) ReadTree(root string) ([]Dependency, error) {
+	modulesDir := filepath.Join(root, "node_modules")
+	if st, err := os.Stat(modulesDir); err != nil {
+		return nil, fmt.Errorf("stat node_modules: %w", err)
+	} else if !st.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", modulesDir)
+	}
+
+	var deps []Dependency
+	err := filepath.WalkDir(modulesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip directories we can't access
+		}
+		if d.IsDir() || d.Name() != "package.json" || !isInstalledPackageDir(filepath.Dir(path)) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var m packageManifest
+		if err := json.Unmarshal(data, &m); err != nil || m.Name == "" || m.Version == "" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		deps = append(deps, Dependency{
+			Name:      m.Name,
+			Version:   m.Version,
+			Ecosystem: EcosystemNpm,
+			Location:  filepath.ToSlash(rel),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// isInstalledPackageDir reports whether dir is node_modules/<name> or
+// node_modules/@scope/<name>, as opposed to a directory inside a package
+// that happens to contain a package.json.
+func isInstalledPackageDir(dir string) bool {
+	parent := filepath.Dir(dir)
+	if filepath.Base(parent) == "node_modules" {
+		return true
+	}
+	return strings.HasPrefix(filepath.Base(parent), "@") && filepath.Base(filepath.Dir(parent)) == "node_modules"
+}
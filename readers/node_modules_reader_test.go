@@ -0,0 +1,58 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNodeModulesReader_ReadTree(t *testing.T) {
+	root := t.TempDir()
+
+	manifests := map[string]string{
+		"node_modules/chalk/package.json":                                      `{"name":"chalk","version":"4.1.2"}`,
+		"node_modules/foo/package.json":                                        `{"name":"foo","version":"1.0.0"}`,
+		"node_modules/foo/node_modules/chalk/package.json":                     `{"name":"chalk","version":"5.6.1"}`,
+		"node_modules/@scope/pkg/package.json":                                 `{"name":"@scope/pkg","version":"0.1.0"}`,
+		"node_modules/foo/lib/package.json":                                    `{"type":"module"}`,
+		"node_modules/.pnpm/left-pad@1.3.0/node_modules/left-pad/package.json": `{"name":"left-pad","version":"1.3.0"}`,
+		// the project's own manifest is not an installed package
+		"package.json": `{"name":"app","version":"1.0.0"}`,
+	}
+	for p, content := range manifests {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", p, err)
+		}
+	}
+
+	deps, err := NewNodeModulesReader().ReadTree(root)
+	if err != nil {
+		t.Fatalf("ReadTree returned error: %v", err)
+	}
+
+	chalks := occurrences(deps, "chalk")
+	if len(chalks) != 2 {
+		t.Fatalf("expected 2 installed copies of chalk, got %+v", chalks)
+	}
+	for _, d := range chalks {
+		if d.Version == "5.6.1" && d.Location != "node_modules/foo/node_modules/chalk" {
+			t.Fatalf("unexpected install path for nested chalk: %q", d.Location)
+		}
+	}
+	if got := versionOf(deps, "@scope/pkg"); got != "0.1.0" {
+		t.Fatalf("expected @scope/pkg=0.1.0 got=%q", got)
+	}
+	if got := versionOf(deps, "left-pad"); got != "1.3.0" {
+		t.Fatalf("expected pnpm store left-pad=1.3.0 got=%q", got)
+	}
+	if got := versionOf(deps, "app"); got != "" {
+		t.Fatalf("project manifest must not be reported")
+	}
+	if len(deps) != 5 {
+		t.Fatalf("expected 5 installed packages, got %d: %+v", len(deps), deps)
+	}
+}
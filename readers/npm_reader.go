@@ -3,7 +3,9 @@ package readers
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -13,20 +15,34 @@ func NewPackageLockReader() DependencyReader { return &PackageLockReader{} }
 
 func (r *PackageLockReader) Name() string { return "package-lock.json" }
 
+// Supports accepts package-lock.json and npm-shrinkwrap.json, which share the
+// same format.
 func (r *PackageLockReader) Supports(filename string) bool {
-	return filename == "package-lock.json"
+	return filename == "package-lock.json" || filename == "npm-shrinkwrap.json"
 }
 
 // internal structures mirror the shape of npm's package-lock.json
 type packageLock struct {
-	Packages map[string]packageInfo `json:"packages"`
+	LockfileVersion int                       `json:"lockfileVersion"`
+	Packages        map[string]packageInfo    `json:"packages"`
+	Dependencies    map[string]lockDependency `json:"dependencies"`
 }
 
 type packageInfo struct {
+	// Name is only set for aliased packages ("npm:real-name@version").
+	Name    string `json:"name"`
 	Version string `json:"version"`
 }
 
-func (r *PackageLockReader) ReadDependencies(path string) (map[string]string, error) {
+// lockDependency is an entry of the nested lockfileVersion 1 "dependencies"
+// tree. lockfileVersion 2 files carry this tree too, for backwards
+// compatibility, next to the flat "packages" map.
+type lockDependency struct {
+	Version      string                    `json:"version"`
+	Dependencies map[string]lockDependency `json:"dependencies"`
+}
+
+func (r *PackageLockReader) ReadDependencies(path string) ([]Dependency, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
@@ -37,19 +53,89 @@ func (r *PackageLockReader) ReadDependencies(path string) (map[string]string, er
 		return nil, fmt.Errorf("unmarshal package-lock: %w", err)
 	}
 
-	deps := make(map[string]string)
-	for pkgPath, info := range pl.Packages {
+	var deps []Dependency
+	if len(pl.Packages) > 0 {
+		deps = packagesFromLock(pl.Packages)
+	} else {
+		deps = dependenciesFromLockTree(pl.Dependencies, "")
+	}
+
+	if len(deps) == 0 {
+		log.Printf("Warning: %s (lockfileVersion %d) contains no dependencies", path, pl.LockfileVersion)
+	}
+
+	return deps, nil
+}
+
+// packagesFromLock reads the flat "packages" map of lockfileVersion 2 and 3.
+func packagesFromLock(packages map[string]packageInfo) []Dependency {
+	// sort the keys so results are stable between runs
+	pkgPaths := make([]string, 0, len(packages))
+	for pkgPath := range packages {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+
+	var deps []Dependency
+	for _, pkgPath := range pkgPaths {
 		if pkgPath == "" { // skip root
 			continue
 		}
 
-		pkgName := packageNameFromLockPath(pkgPath)
-		if info.Version != "" {
-			deps[pkgName] = info.Version
+		info := packages[pkgPath]
+		if info.Version == "" {
+			continue
+		}
+		name := info.Name
+		if name == "" {
+			name = packageNameFromLockPath(pkgPath)
+		}
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   info.Version,
+			Ecosystem: EcosystemNpm,
+			Location:  pkgPath,
+		})
+	}
+
+	return deps
+}
+
+// dependenciesFromLockTree walks the nested lockfileVersion 1 "dependencies"
+// tree. Locations are reported as install paths (node_modules/a/node_modules/b)
+// to match those of newer lockfiles.
+func dependenciesFromLockTree(tree map[string]lockDependency, parent string) []Dependency {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var deps []Dependency
+	for _, name := range names {
+		dep := tree[name]
+		location := parent + "node_modules/" + name
+
+		realName, version := name, dep.Version
+		if target, ok := strings.CutPrefix(version, "npm:"); ok {
+			// aliased install: "npm:real-name@1.2.3"
+			if aliased, aliasVersion := splitNpmDescriptor(target); aliasVersion != "" {
+				realName, version = aliased, aliasVersion
+			}
 		}
+		if version != "" {
+			deps = append(deps, Dependency{
+				Name:      realName,
+				Version:   version,
+				Ecosystem: EcosystemNpm,
+				Location:  location,
+			})
+		}
+
+		deps = append(deps, dependenciesFromLockTree(dep.Dependencies, location+"/")...)
 	}
 
-	return deps, nil
+	return deps
 }
 
 func packageNameFromLockPath(pkgPath string) string {
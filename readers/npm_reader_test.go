@@ -28,10 +28,10 @@ func TestPackageLockReader_ReadDependencies(t *testing.T) {
 		t.Fatalf("ReadDependencies returned error: %v", err)
 	}
 
-	if got := deps["left-pad"]; got != "1.2.3" {
+	if got := versionOf(deps, "left-pad"); got != "1.2.3" {
 		t.Fatalf("expected left-pad=1.2.3 got=%q", got)
 	}
-	if got := deps["@scope/pkg"]; got != "0.1.0" {
+	if got := versionOf(deps, "@scope/pkg"); got != "0.1.0" {
 		t.Fatalf("expected @scope/pkg=0.1.0 got=%q", got)
 	}
 }
@@ -58,13 +58,101 @@ func TestPackageLockReader_ReadDependencies_NestedPackagePath(t *testing.T) {
 		t.Fatalf("ReadDependencies returned error: %v", err)
 	}
 
-	if got := deps["chalk"]; got != "5.6.1" {
+	if got := versionOf(deps, "chalk"); got != "5.6.1" {
 		t.Fatalf("expected chalk=5.6.1 got=%q", got)
 	}
-	if got := deps["@scope/pkg"]; got != "0.1.0" {
+	if got := versionOf(deps, "@scope/pkg"); got != "0.1.0" {
 		t.Fatalf("expected @scope/pkg=0.1.0 got=%q", got)
 	}
-	if _, exists := deps["log-symbols/node_modules/chalk"]; exists {
-		t.Fatalf("unexpected nested package path key present in deps")
+	if got := versionOf(deps, "log-symbols/node_modules/chalk"); got != "" {
+		t.Fatalf("unexpected nested package path name present in deps")
+	}
+}
+
+func TestPackageLockReader_ReadDependencies_EveryOccurrence(t *testing.T) {
+	tmpDir := t.TempDir()
+	fpath := filepath.Join(tmpDir, "package-lock.json")
+
+	data := `{
+  "packages": {
+    "": { "version": "1.0.0" },
+    "node_modules/chalk": { "version": "4.1.2" },
+    "node_modules/foo/node_modules/chalk": { "version": "5.6.1" }
+  }
+}`
+
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write tmp package-lock: %v", err)
+	}
+
+	r := NewPackageLockReader()
+	deps, err := r.ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	chalks := occurrences(deps, "chalk")
+	if len(chalks) != 2 {
+		t.Fatalf("expected 2 occurrences of chalk, got %d", len(chalks))
+	}
+	want := map[string]string{
+		"node_modules/chalk":                  "4.1.2",
+		"node_modules/foo/node_modules/chalk": "5.6.1",
+	}
+	for _, d := range chalks {
+		if want[d.Location] != d.Version {
+			t.Fatalf("unexpected occurrence %+v", d)
+		}
+		if d.Ecosystem != EcosystemNpm {
+			t.Fatalf("expected npm ecosystem, got %q", d.Ecosystem)
+		}
+	}
+}
+
+func TestPackageLockReader_ReadDependencies_LockfileV1(t *testing.T) {
+	tmpDir := t.TempDir()
+	fpath := filepath.Join(tmpDir, "npm-shrinkwrap.json")
+
+	data := `{
+  "name": "app",
+  "lockfileVersion": 1,
+  "dependencies": {
+    "chalk": {
+      "version": "4.1.2",
+      "dependencies": {
+        "ansi-styles": { "version": "4.3.0" }
+      }
+    },
+    "@scope/pkg": { "version": "0.1.0" },
+    "string-width-cjs": { "version": "npm:string-width@4.2.3" }
+  }
+}`
+
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write tmp npm-shrinkwrap: %v", err)
+	}
+
+	r := NewPackageLockReader()
+	if !r.Supports("npm-shrinkwrap.json") {
+		t.Fatalf("expected reader to support npm-shrinkwrap.json")
+	}
+
+	deps, err := r.ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := versionOf(deps, "chalk"); got != "4.1.2" {
+		t.Fatalf("expected chalk=4.1.2 got=%q", got)
+	}
+	if got := versionOf(deps, "@scope/pkg"); got != "0.1.0" {
+		t.Fatalf("expected @scope/pkg=0.1.0 got=%q", got)
+	}
+	if got := versionOf(deps, "string-width"); got != "4.2.3" {
+		t.Fatalf("expected aliased string-width=4.2.3 got=%q", got)
+	}
+	styles := occurrences(deps, "ansi-styles")
+	if len(styles) != 1 || styles[0].Location != "node_modules/chalk/node_modules/ansi-styles" {
+		t.Fatalf("expected nested ansi-styles with install path, got %+v", styles)
 	}
 }
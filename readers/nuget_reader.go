@@ -0,0 +1,172 @@
+package readers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// NuGetLockReader reads NuGet packages.lock.json files.
+type NuGetLockReader struct{}
+
+func NewNuGetLockReader() DependencyReader { return &NuGetLockReader{} }
+
+func (r *NuGetLockReader) Name() string { return "packages.lock.json" }
+
+func (r *NuGetLockReader) Supports(filename string) bool {
+	return filename == "packages.lock.json"
+}
+
+// nugetLock maps target framework -> package id -> entry.
+type nugetLock struct {
+	Dependencies map[string]map[string]nugetLockEntry `json:"dependencies"`
+}
+
+type nugetLockEntry struct {
+	Type     string `json:"type"` // Direct, Transitive, CentralTransitive or Project
+	Resolved string `json:"resolved"`
+}
+
+// ReadDependencies reports direct and transitive packages of every target
+// framework. A package resolved to the same version for several frameworks
+// is reported once, with the first framework as its location.
+func (r *NuGetLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var nl nugetLock
+	if err := json.Unmarshal(data, &nl); err != nil {
+		return nil, fmt.Errorf("unmarshal packages.lock.json: %w", err)
+	}
+
+	frameworks := make([]string, 0, len(nl.Dependencies))
+	for fw := range nl.Dependencies {
+		frameworks = append(frameworks, fw)
+	}
+	sort.Strings(frameworks)
+
+	seen := make(map[string]bool)
+	var deps []Dependency
+	for _, fw := range frameworks {
+		ids := make([]string, 0, len(nl.Dependencies[fw]))
+		for id := range nl.Dependencies[fw] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			entry := nl.Dependencies[fw][id]
+			if entry.Resolved == "" || entry.Type == "Project" {
+				continue
+			}
+			key := strings.ToLower(id) + "@" + entry.Resolved
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			deps = append(deps, Dependency{
+				Name:      id,
+				Version:   entry.Resolved,
+				Ecosystem: EcosystemNuGet,
+				Location:  fw,
+			})
+		}
+	}
+
+	return deps, nil
+}
+
+// ProjectFileReader reads <PackageReference> items from MSBuild project
+// files. It is a fallback for projects without packages.lock.json and only
+// sees direct references with a literal version; a project file next to a
+// packages.lock.json yields nothing, since the lockfile already lists its
+// packages.
+type ProjectFileReader struct{}
+
+func NewProjectFileReader() DependencyReader { return &ProjectFileReader{} }
+
+func (r *ProjectFileReader) Name() string { return "csproj" }
+
+func (r *ProjectFileReader) Patterns() []string {
+	return []string{"*.csproj", "*.fsproj", "*.vbproj"}
+}
+
+func (r *ProjectFileReader) Supports(filename string) bool {
+	return matchesAny(filename, r.Patterns())
+}
+
+type msbuildProject struct {
+	ItemGroups []struct {
+		PackageReferences []packageReference `xml:"PackageReference"`
+	} `xml:"ItemGroup"`
+}
+
+type packageReference struct {
+	Include string `xml:"Include,attr"`
+	Update  string `xml:"Update,attr"`
+	// Version can be given as an attribute or as a child element.
+	VersionAttr    string `xml:"Version,attr"`
+	VersionElement string `xml:"Version"`
+}
+
+func (r *ProjectFileReader) ReadDependencies(path string) ([]Dependency, error) {
+	deps, _, err := r.ReadDependenciesAndSources(path)
+	return deps, err
+}
+
+// ReadDependenciesAndSources also returns the sibling packages.lock.json,
+// whether or not it exists, since its presence decides the result.
+func (r *ProjectFileReader) ReadDependenciesAndSources(path string) ([]Dependency, []string, error) {
+	lockfile := filepath.Join(filepath.Dir(path), "packages.lock.json")
+	if abs, err := filepath.Abs(lockfile); err == nil {
+		lockfile = abs
+	}
+	sources := []string{lockfile}
+	if _, err := os.Stat(lockfile); err == nil {
+		return nil, sources, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var p msbuildProject
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal project file: %w", err)
+	}
+
+	var deps []Dependency
+	for _, group := range p.ItemGroups {
+		for _, ref := range group.PackageReferences {
+			id := ref.Include
+			if id == "" {
+				id = ref.Update
+			}
+			version := strings.TrimSpace(ref.VersionAttr)
+			if version == "" {
+				version = strings.TrimSpace(ref.VersionElement)
+			}
+			// versions from MSBuild properties or central package
+			// management can't be resolved from this file alone
+			if id == "" || version == "" || strings.Contains(version, "$(") {
+				continue
+			}
+			deps = append(deps, Dependency{
+				Name:      id,
+				Version:   version,
+				Ecosystem: EcosystemNuGet,
+				Location:  "PackageReference",
+			})
+		}
+	}
+
+	return deps, sources, nil
+}
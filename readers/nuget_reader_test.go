@@ -0,0 +1,116 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNuGetLockReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "packages.lock.json")
+	data := `{
+  "version": 1,
+  "dependencies": {
+    "net6.0": {
+      "Newtonsoft.Json": { "type": "Direct", "requested": "[13.0.1, )", "resolved": "13.0.1", "contentHash": "abc" },
+      "System.Text.Json": { "type": "Transitive", "resolved": "6.0.0" },
+      "MyLib": { "type": "Project" }
+    },
+    "net8.0": {
+      "Newtonsoft.Json": { "type": "Direct", "requested": "[13.0.1, )", "resolved": "13.0.1" },
+      "System.Text.Json": { "type": "Transitive", "resolved": "8.0.0" }
+    }
+  }
+}`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write packages.lock.json: %v", err)
+	}
+
+	deps, err := NewNuGetLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := len(occurrences(deps, "Newtonsoft.Json")); got != 1 {
+		t.Fatalf("expected Newtonsoft.Json to be reported once, got %d", got)
+	}
+	if got := len(occurrences(deps, "System.Text.Json")); got != 2 {
+		t.Fatalf("expected both System.Text.Json versions, got %d", got)
+	}
+	if got := versionOf(deps, "MyLib"); got != "" {
+		t.Fatalf("project references must not be reported")
+	}
+}
+
+func TestProjectFileReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "App.csproj")
+	data := `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+    <PackageReference Include="Serilog">
+      <Version>3.1.1</Version>
+    </PackageReference>
+    <PackageReference Include="Centrally.Managed" />
+    <PackageReference Include="From.Property" Version="$(FromPropertyVersion)" />
+  </ItemGroup>
+</Project>`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write App.csproj: %v", err)
+	}
+
+	deps, err := NewProjectFileReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := versionOf(deps, "Newtonsoft.Json"); got != "13.0.1" {
+		t.Fatalf("expected Newtonsoft.Json=13.0.1 got=%q", got)
+	}
+	if got := versionOf(deps, "Serilog"); got != "3.1.1" {
+		t.Fatalf("expected Serilog=3.1.1 got=%q", got)
+	}
+	if len(deps) != 2 {
+		t.Fatalf("expected 2 references, got %d: %+v", len(deps), deps)
+	}
+}
+
+func TestProjectFileReader_SkipsProjectWithLockfile(t *testing.T) {
+	dir := t.TempDir()
+	fpath := filepath.Join(dir, "App.csproj")
+	data := `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+  </ItemGroup>
+</Project>`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write App.csproj: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "packages.lock.json"), []byte(`{"version": 1, "dependencies": {}}`), 0644); err != nil {
+		t.Fatalf("write packages.lock.json: %v", err)
+	}
+
+	deps, err := NewProjectFileReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Fatalf("expected the lockfile to take precedence, got %+v", deps)
+	}
+
+	// the lockfile is a source either way, so deleting it triggers a rescan
+	lockfile := filepath.Join(dir, "packages.lock.json")
+	_, sources, err := NewProjectFileReader().(SourceReader).ReadDependenciesAndSources(fpath)
+	if err != nil || len(sources) != 1 || sources[0] != lockfile {
+		t.Fatalf("expected the lockfile as a source, got %v (%v)", sources, err)
+	}
+	if err := os.Remove(lockfile); err != nil {
+		t.Fatal(err)
+	}
+	deps, sources, err = NewProjectFileReader().(SourceReader).ReadDependenciesAndSources(fpath)
+	if err != nil || len(deps) != 1 || len(sources) != 1 || sources[0] != lockfile {
+		t.Fatalf("expected the project's references and the missing lockfile as a source, got %+v %v (%v)", deps, sources, err)
+	}
+}
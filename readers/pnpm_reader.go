@@ -0,0 +1,181 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PnpmLockReader reads pnpm-lock.yaml files (lockfile versions 5.x, 6.x and
+// 9.x). Every resolved package is listed under "packages" (and, from 9.0, the
+// peer-resolved variants under "snapshots").
+type PnpmLockReader struct{}
+
+func NewPnpmLockReader() DependencyReader { return &PnpmLockReader{} }
+
+func (r *PnpmLockReader) Name() string { return "pnpm-lock.yaml" }
+
+func (r *PnpmLockReader) Supports(filename string) bool {
+	return filename == "pnpm-lock.yaml"
+}
+
+// pnpmEntry is a single package key under packages: or snapshots:, together
+// with the name/version fields pnpm writes for non-registry packages.
+type pnpmEntry struct {
+	key     string
+	name    string
+	version string
+}
+
+func (r *PnpmLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	lockfileVersion, entries, err := parsePnpmLock(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse pnpm-lock.yaml: %w", err)
+	}
+
+	// the same package@version shows up once under packages and once per
+	// peer combination under snapshots; report it once
+	seen := make(map[string]bool)
+	var deps []Dependency
+	for _, e := range entries {
+		name, version := e.name, e.version
+		if name == "" || version == "" {
+			if lockfileVersion < 9 && !strings.HasPrefix(e.key, "/") {
+				continue // non-registry keys without name/version fields
+			}
+			n, v, ok := parsePnpmKey(e.key, lockfileVersion)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				name = n
+			}
+			if version == "" {
+				version = v
+			}
+		}
+		if !isPlainVersion(version) {
+			continue // git, tarball and file: dependencies
+		}
+
+		id := name + "@" + version
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   version,
+			Ecosystem: EcosystemNpm,
+			Location:  e.key,
+		})
+	}
+
+	return deps, nil
+}
+
+// parsePnpmLock returns the lockfile major version and the entries found
+// under the top-level packages: and snapshots: mappings.
+func parsePnpmLock(data []byte) (int, []pnpmEntry, error) {
+	lockfileVersion := 0
+	var entries []pnpmEntry
+	var cur *pnpmEntry
+	section := ""
+
+	flush := func() {
+		if cur != nil {
+			entries = append(entries, *cur)
+			cur = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case indent == 0:
+			flush()
+			key, value, _ := strings.Cut(trimmed, ":")
+			section = key
+			if key == "lockfileVersion" {
+				v := unquote(strings.TrimSpace(value))
+				major, _, _ := strings.Cut(v, ".")
+				n, err := strconv.Atoi(major)
+				if err != nil {
+					return 0, nil, fmt.Errorf("invalid lockfileVersion %q", v)
+				}
+				lockfileVersion = n
+			}
+		case section != "packages" && section != "snapshots":
+			continue
+		case indent == 2 && strings.HasSuffix(trimmed, ":"):
+			flush()
+			cur = &pnpmEntry{key: unquote(strings.TrimSuffix(trimmed, ":"))}
+		case indent == 4 && cur != nil:
+			key, value, ok := strings.Cut(trimmed, ":")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "name":
+				cur.name = unquote(strings.TrimSpace(value))
+			case "version":
+				cur.version = unquote(strings.TrimSpace(value))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, nil, err
+	}
+	flush()
+
+	return lockfileVersion, entries, nil
+}
+
+// parsePnpmKey extracts name and version from a packages/snapshots key:
+//
+//	5.x: /@scope/name/1.2.3_peer@4.0.0
+//	6.x: /@scope/name@1.2.3(peer@4.0.0)
+//	9.x: @scope/name@1.2.3(peer@4.0.0)
+func parsePnpmKey(key string, lockfileVersion int) (string, string, bool) {
+	key = strings.TrimPrefix(key, "/")
+
+	if lockfileVersion > 0 && lockfileVersion < 6 {
+		slash := strings.LastIndex(key, "/")
+		if slash <= 0 {
+			return "", "", false
+		}
+		name, version := key[:slash], key[slash+1:]
+		// peer suffixes use "_" and replace "/" in scoped peers with "+"
+		version, _, _ = strings.Cut(version, "_")
+		return name, version, name != "" && version != ""
+	}
+
+	if paren := strings.Index(key, "("); paren >= 0 {
+		key = key[:paren]
+	}
+	name, version := splitNpmDescriptor(key)
+	return name, version, name != "" && version != ""
+}
+
+// isPlainVersion reports whether v looks like a registry version rather than
+// a URL, path or git reference.
+func isPlainVersion(v string) bool {
+	return v != "" && !strings.ContainsAny(v, ":/")
+}
@@ -0,0 +1,119 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readPnpmLock(t *testing.T, data string) []Dependency {
+	t.Helper()
+	fpath := filepath.Join(t.TempDir(), "pnpm-lock.yaml")
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write tmp pnpm-lock.yaml: %v", err)
+	}
+
+	deps, err := NewPnpmLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+	return deps
+}
+
+func TestPnpmLockReader_ReadDependencies_V5(t *testing.T) {
+	deps := readPnpmLock(t, `lockfileVersion: 5.4
+
+specifiers:
+  left-pad: ^1.3.0
+
+dependencies:
+  left-pad: 1.3.0
+
+packages:
+
+  /left-pad/1.3.0:
+    resolution: {integrity: sha512-abc}
+    dev: false
+
+  /@scope/pkg/0.1.0_@types+react@17.0.2:
+    resolution: {integrity: sha512-def}
+    dev: false
+
+  github.com/user/repo/0123abc:
+    resolution: {tarball: https://codeload.github.com/user/repo/tar.gz/0123abc}
+    name: repo
+    version: 2.0.0
+`)
+
+	if got := versionOf(deps, "left-pad"); got != "1.3.0" {
+		t.Fatalf("expected left-pad=1.3.0 got=%q", got)
+	}
+	if got := versionOf(deps, "@scope/pkg"); got != "0.1.0" {
+		t.Fatalf("expected @scope/pkg=0.1.0 got=%q", got)
+	}
+	if got := versionOf(deps, "repo"); got != "2.0.0" {
+		t.Fatalf("expected repo=2.0.0 from name/version fields got=%q", got)
+	}
+}
+
+func TestPnpmLockReader_ReadDependencies_V6(t *testing.T) {
+	deps := readPnpmLock(t, `lockfileVersion: '6.0'
+
+packages:
+
+  /chalk@5.6.1:
+    resolution: {integrity: sha512-abc}
+    dev: false
+
+  /@scope/pkg@0.1.0(react@17.0.2)(react-dom@17.0.2):
+    resolution: {integrity: sha512-def}
+    peerDependencies:
+      react: ^17
+    dev: false
+`)
+
+	if got := versionOf(deps, "chalk"); got != "5.6.1" {
+		t.Fatalf("expected chalk=5.6.1 got=%q", got)
+	}
+	if got := versionOf(deps, "@scope/pkg"); got != "0.1.0" {
+		t.Fatalf("expected @scope/pkg=0.1.0 got=%q", got)
+	}
+}
+
+func TestPnpmLockReader_ReadDependencies_V9(t *testing.T) {
+	deps := readPnpmLock(t, `lockfileVersion: '9.0'
+
+importers:
+
+  .:
+    dependencies:
+      '@scope/pkg':
+        specifier: ^0.1.0
+        version: 0.1.0(react@17.0.2)
+
+packages:
+
+  '@scope/pkg@0.1.0':
+    resolution: {integrity: sha512-def}
+
+  local@file:../local:
+    resolution: {directory: ../local, type: directory}
+
+snapshots:
+
+  '@scope/pkg@0.1.0(react@17.0.2)':
+    dependencies:
+      react: 17.0.2
+
+  '@scope/pkg@0.1.0(react@18.2.0)':
+    dependencies:
+      react: 18.2.0
+`)
+
+	if got := occurrences(deps, "@scope/pkg"); len(got) != 1 || got[0].Version != "0.1.0" {
+		t.Fatalf("expected a single @scope/pkg=0.1.0, got %+v", got)
+	}
+	if got := versionOf(deps, "local"); got != "" {
+		t.Fatalf("file: dependencies must not be reported, got %q", got)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/xml"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 )
 
 type PomReader struct{}
@@ -16,10 +19,46 @@ func (r *PomReader) Supports(filename string) bool {
 	return filename == "pom.xml"
 }
 
+// maxParentDepth bounds how many local parent poms are followed.
+const maxParentDepth = 10
+
+// maxInterpolationDepth bounds how many levels of nested ${...} references
+// are expanded, guarding against self-referencing properties.
+const maxInterpolationDepth = 10
+
+// defaultPluginGroupID is the groupId Maven assumes for plugins that omit it.
+const defaultPluginGroupID = "org.apache.maven.plugins"
+
 type pomXML struct {
-	Dependencies struct {
-		Dependency []pomDependency `xml:"dependency"`
-	} `xml:"dependencies"`
+	GroupID              string                  `xml:"groupId"`
+	ArtifactID           string                  `xml:"artifactId"`
+	Version              string                  `xml:"version"`
+	Parent               *pomParent              `xml:"parent"`
+	Properties           pomProperties           `xml:"properties"`
+	Dependencies         pomDependencies         `xml:"dependencies"`
+	DependencyManagement pomDependencyManagement `xml:"dependencyManagement"`
+	Build                pomBuild                `xml:"build"`
+	Profiles             struct {
+		Profile []pomProfile `xml:"profile"`
+	} `xml:"profiles"`
+}
+
+type pomParent struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	// RelativePath is nil when the element is absent (Maven then defaults
+	// to ../pom.xml) and "" for an explicit <relativePath/>, which disables
+	// the local lookup.
+	RelativePath *string `xml:"relativePath"`
+}
+
+type pomDependencies struct {
+	Dependency []pomDependency `xml:"dependency"`
+}
+
+type pomDependencyManagement struct {
+	Dependencies pomDependencies `xml:"dependencies"`
 }
 
 type pomDependency struct {
@@ -28,7 +67,114 @@ type pomDependency struct {
 	Version    string `xml:"version"`
 }
 
-func (r *PomReader) ReadDependencies(path string) (map[string]string, error) {
+type pomBuild struct {
+	Plugins          pomPlugins `xml:"plugins"`
+	PluginManagement struct {
+		Plugins pomPlugins `xml:"plugins"`
+	} `xml:"pluginManagement"`
+}
+
+type pomPlugins struct {
+	Plugin []pomPlugin `xml:"plugin"`
+}
+
+type pomPlugin struct {
+	GroupID      string          `xml:"groupId"`
+	ArtifactID   string          `xml:"artifactId"`
+	Version      string          `xml:"version"`
+	Dependencies pomDependencies `xml:"dependencies"`
+}
+
+type pomProfile struct {
+	ID                   string                  `xml:"id"`
+	Properties           pomProperties           `xml:"properties"`
+	Dependencies         pomDependencies         `xml:"dependencies"`
+	DependencyManagement pomDependencyManagement `xml:"dependencyManagement"`
+	Build                pomBuild                `xml:"build"`
+}
+
+// pomProperties collects the free-form children of <properties>.
+type pomProperties map[string]string
+
+func (p *pomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	props := make(pomProperties)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			props[t.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			*p = props
+			return nil
+		}
+	}
+}
+
+// pomModel is a pom together with what it inherits from its local parents.
+type pomModel struct {
+	pom        pomXML
+	properties map[string]string
+	// managedDeps and managedPlugins are the dependencyManagement and
+	// pluginManagement entries of the whole parent chain, parents first.
+	// They are interpolated with the child's properties, as Maven does.
+	managedDeps    []pomDependency
+	managedPlugins []pomPlugin
+}
+
+func (r *PomReader) ReadDependencies(path string) ([]Dependency, error) {
+	deps, _, err := r.ReadDependenciesAndSources(path)
+	return deps, err
+}
+
+// ReadDependenciesAndSources also returns the parent poms that were looked
+// for, whose properties and managed versions the result depends on.
+func (r *PomReader) ReadDependenciesAndSources(path string) ([]Dependency, []string, error) {
+	visited := make(map[string]bool)
+	model, err := loadPomModel(path, visited, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	managed := managedVersions(model.managedDeps, model.properties)
+	managedPlugins := managedPluginVersions(model.managedPlugins, model.properties)
+
+	c := pomCollector{seen: make(map[string]bool)}
+	c.addDependencies(model.pom.Dependencies, model.properties, managed, "dependencies")
+	c.addPlugins(model.pom.Build.Plugins, model.properties, managedPlugins, "build/plugins")
+
+	for _, profile := range model.pom.Profiles.Profile {
+		props := overlay(model.properties, profile.Properties)
+		managed := managedVersions(append(slices.Clone(model.managedDeps), profile.DependencyManagement.Dependencies.Dependency...), props)
+		managedPlugins := managedPluginVersions(append(slices.Clone(model.managedPlugins), profile.Build.PluginManagement.Plugins.Plugin...), props)
+
+		prefix := "profiles/" + profile.ID + "/"
+		c.addDependencies(profile.Dependencies, props, managed, prefix+"dependencies")
+		c.addPlugins(profile.Build.Plugins, props, managedPlugins, prefix+"build/plugins")
+		c.addDependencies(profile.DependencyManagement.Dependencies, props, nil, prefix+"dependencyManagement")
+		c.addPlugins(profile.Build.PluginManagement.Plugins, props, nil, prefix+"build/pluginManagement")
+	}
+
+	// Declarations that only pin a version are reported too (a parent pom
+	// is often where a bad version is introduced), unless the same
+	// coordinate was already reported above.
+	c.addDependencies(model.pom.DependencyManagement.Dependencies, model.properties, nil, "dependencyManagement")
+	c.addPlugins(model.pom.Build.PluginManagement.Plugins, model.properties, nil, "build/pluginManagement")
+
+	return c.deps, otherSources(path, visited), nil
+}
+
+// loadPomModel parses the pom at path and merges in properties and managed
+// versions from parent poms that can be found on disk via <relativePath>.
+// No repository or network lookups are made. visited collects every pom
+// that was looked for, including missing ones.
+func loadPomModel(path string, visited map[string]bool, depth int) (*pomModel, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
@@ -39,14 +185,218 @@ func (r *PomReader) ReadDependencies(path string) (map[string]string, error) {
 		return nil, fmt.Errorf("unmarshal pom.xml: %w", err)
 	}
 
-	deps := make(map[string]string)
-	for _, d := range p.Dependencies.Dependency {
+	if abs, err := filepath.Abs(path); err == nil {
+		visited[abs] = true
+	}
+
+	model := &pomModel{
+		pom:        p,
+		properties: make(map[string]string),
+	}
+
+	if parent := loadParentModel(path, p.Parent, visited, depth); parent != nil {
+		model.properties = overlay(model.properties, parent.properties)
+		model.managedDeps = parent.managedDeps
+		model.managedPlugins = parent.managedPlugins
+	}
+
+	// project coordinates fall back to the parent's, as in Maven
+	groupID, version := p.GroupID, p.Version
+	if p.Parent != nil {
+		if groupID == "" {
+			groupID = p.Parent.GroupID
+		}
+		if version == "" {
+			version = p.Parent.Version
+		}
+		model.properties["project.parent.groupId"] = p.Parent.GroupID
+		model.properties["project.parent.artifactId"] = p.Parent.ArtifactID
+		model.properties["project.parent.version"] = p.Parent.Version
+		model.properties["parent.version"] = p.Parent.Version
+	}
+	model.properties["project.groupId"] = groupID
+	model.properties["project.artifactId"] = p.ArtifactID
+	model.properties["project.version"] = version
+	model.properties["pom.version"] = version
+	model.properties["version"] = version
+	model.properties = overlay(model.properties, p.Properties)
+	model.managedDeps = append(model.managedDeps, p.DependencyManagement.Dependencies.Dependency...)
+	model.managedPlugins = append(model.managedPlugins, p.Build.PluginManagement.Plugins.Plugin...)
+
+	return model, nil
+}
+
+// loadParentModel loads the parent pom referenced by parent, if it exists on
+// disk and its coordinates match the declaration.
+func loadParentModel(childPath string, parent *pomParent, visited map[string]bool, depth int) *pomModel {
+	if parent == nil || depth >= maxParentDepth {
+		return nil
+	}
+
+	rel := "../pom.xml"
+	if parent.RelativePath != nil {
+		rel = strings.TrimSpace(*parent.RelativePath)
+	}
+	if rel == "" {
+		return nil
+	}
+
+	parentPath := filepath.Join(filepath.Dir(childPath), filepath.FromSlash(rel))
+	if st, err := os.Stat(parentPath); err == nil && st.IsDir() {
+		parentPath = filepath.Join(parentPath, "pom.xml")
+	}
+	abs, err := filepath.Abs(parentPath)
+	if err != nil {
+		abs = parentPath
+	}
+	if visited[abs] {
+		return nil
+	}
+	visited[abs] = true
+
+	model, err := loadPomModel(parentPath, visited, depth+1)
+	if err != nil {
+		return nil
+	}
+
+	// the pom found at relativePath must be the declared parent
+	parentGroupID := model.pom.GroupID
+	if parentGroupID == "" && model.pom.Parent != nil {
+		parentGroupID = model.pom.Parent.GroupID
+	}
+	if parentGroupID != parent.GroupID || model.pom.ArtifactID != parent.ArtifactID {
+		return nil
+	}
+
+	return model
+}
+
+// pomCollector accumulates dependencies, reporting each coordinate once.
+type pomCollector struct {
+	deps []Dependency
+	seen map[string]bool
+}
+
+func (c *pomCollector) add(groupID, artifactID, version, location string) {
+	if groupID == "" || artifactID == "" || version == "" || strings.Contains(version, "${") {
+		return // unresolvable without a repository
+	}
+
+	name := groupID + ":" + artifactID
+	if c.seen[name+"@"+version] {
+		return
+	}
+	c.seen[name+"@"+version] = true
+
+	c.deps = append(c.deps, Dependency{
+		Name:      name,
+		Version:   version,
+		Ecosystem: EcosystemMaven,
+		Location:  location,
+	})
+}
+
+func (c *pomCollector) addDependencies(deps pomDependencies, props, managed map[string]string, location string) {
+	for _, d := range deps.Dependency {
+		groupID := interpolate(d.GroupID, props)
+		artifactID := interpolate(d.ArtifactID, props)
+		version := interpolate(d.Version, props)
+		if version == "" {
+			version = managed[groupID+":"+artifactID]
+		}
+		c.add(groupID, artifactID, version, location)
+	}
+}
+
+func (c *pomCollector) addPlugins(plugins pomPlugins, props, managed map[string]string, location string) {
+	for _, p := range plugins.Plugin {
+		groupID := interpolate(p.GroupID, props)
+		if groupID == "" {
+			groupID = defaultPluginGroupID
+		}
+		artifactID := interpolate(p.ArtifactID, props)
+		version := interpolate(p.Version, props)
+		if version == "" {
+			version = managed[groupID+":"+artifactID]
+		}
+		c.add(groupID, artifactID, version, location)
+		c.addDependencies(p.Dependencies, props, nil, location+"/"+artifactID+"/dependencies")
+	}
+}
+
+// managedVersions maps groupId:artifactId to the managed version. Later
+// entries (closer to the child) win.
+func managedVersions(deps []pomDependency, props map[string]string) map[string]string {
+	managed := make(map[string]string)
+	for _, d := range deps {
 		if d.Version == "" {
 			continue
 		}
-		pkgName := d.GroupID + ":" + d.ArtifactID
-		deps[pkgName] = d.Version
+		managed[interpolate(d.GroupID, props)+":"+interpolate(d.ArtifactID, props)] = interpolate(d.Version, props)
 	}
+	return managed
+}
 
-	return deps, nil
+func managedPluginVersions(plugins []pomPlugin, props map[string]string) map[string]string {
+	managed := make(map[string]string)
+	for _, p := range plugins {
+		if p.Version == "" {
+			continue
+		}
+		groupID := interpolate(p.GroupID, props)
+		if groupID == "" {
+			groupID = defaultPluginGroupID
+		}
+		managed[groupID+":"+interpolate(p.ArtifactID, props)] = interpolate(p.Version, props)
+	}
+	return managed
+}
+
+// interpolate replaces ${name} references with values from props. Values
+// may themselves contain references; unknown references are left in place.
+func interpolate(s string, props map[string]string) string {
+	s = strings.TrimSpace(s)
+	for i := 0; i < maxInterpolationDepth && strings.Contains(s, "${"); i++ {
+		var b strings.Builder
+		rest := s
+		changed := false
+		for {
+			start := strings.Index(rest, "${")
+			if start < 0 {
+				b.WriteString(rest)
+				break
+			}
+			end := strings.Index(rest[start:], "}")
+			if end < 0 {
+				b.WriteString(rest)
+				break
+			}
+			end += start
+			b.WriteString(rest[:start])
+			if v, ok := props[rest[start+2:end]]; ok && v != "" {
+				b.WriteString(v)
+				changed = true
+			} else {
+				b.WriteString(rest[start : end+1])
+			}
+			rest = rest[end+1:]
+		}
+		s = b.String()
+		if !changed {
+			break
+		}
+	}
+	return s
+}
+
+// overlay returns a copy of base with the entries of top added on top.
+func overlay(base, top map[string]string) map[string]string {
+	out := make(map[string]string, len(base)+len(top))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range top {
+		out[k] = v
+	}
+	return out
 }
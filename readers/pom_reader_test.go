@@ -3,6 +3,7 @@ package readers
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -31,7 +32,178 @@ func TestPomReader_ReadDependencies(t *testing.T) {
 		t.Fatalf("ReadDependencies returned error: %v", err)
 	}
 
-	if deps["com.example:evil"] != "1.2.3" {
-		t.Fatalf("expected com.example:evil=1.2.3 got=%q", deps["com.example:evil"])
+	if versionOf(deps, "com.example:evil") != "1.2.3" {
+		t.Fatalf("expected com.example:evil=1.2.3 got=%q", versionOf(deps, "com.example:evil"))
+	}
+}
+
+func TestPomReader_ReadDependencies_Resolution(t *testing.T) {
+	tmpDir := t.TempDir()
+	childDir := filepath.Join(tmpDir, "child")
+	if err := os.MkdirAll(childDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	parent := `<?xml version="1.0"?>
+<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>2.0.0</version>
+  <properties>
+    <log4j.version>2.14.0</log4j.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.apache.logging.log4j</groupId>
+        <artifactId>log4j-core</artifactId>
+        <version>${log4j.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <artifactId>maven-shade-plugin</artifactId>
+          <version>3.2.4</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+  </build>
+</project>`
+
+	child := `<?xml version="1.0"?>
+<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0.0</version>
+  </parent>
+  <artifactId>child</artifactId>
+  <properties>
+    <log4j.version>2.14.1</log4j.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.apache.logging.log4j</groupId>
+      <artifactId>log4j-core</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>sibling</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>unresolved</artifactId>
+      <version>${missing.version}</version>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-shade-plugin</artifactId>
+        <dependencies>
+          <dependency>
+            <groupId>com.example</groupId>
+            <artifactId>plugin-dep</artifactId>
+            <version>1.0.0</version>
+          </dependency>
+        </dependencies>
+      </plugin>
+    </plugins>
+  </build>
+  <profiles>
+    <profile>
+      <id>extra</id>
+      <properties>
+        <extra.version>0.9.0</extra.version>
+      </properties>
+      <dependencies>
+        <dependency>
+          <groupId>com.example</groupId>
+          <artifactId>evil</artifactId>
+          <version>${extra.version}</version>
+        </dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>`
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "pom.xml"), []byte(parent), 0644); err != nil {
+		t.Fatalf("write parent pom: %v", err)
+	}
+	fpath := filepath.Join(childDir, "pom.xml")
+	if err := os.WriteFile(fpath, []byte(child), 0644); err != nil {
+		t.Fatalf("write child pom: %v", err)
+	}
+
+	deps, err := NewPomReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	want := map[string]string{
+		// managed in the parent, interpolated with the child's property
+		"org.apache.logging.log4j:log4j-core": "2.14.1",
+		// project coordinates inherited from the parent
+		"com.example:sibling": "2.0.0",
+		// plugin version from the parent's pluginManagement
+		"org.apache.maven.plugins:maven-shade-plugin": "3.2.4",
+		"com.example:plugin-dep":                      "1.0.0",
+		"com.example:evil":                            "0.9.0",
+	}
+	for name, version := range want {
+		if got := versionOf(deps, name); got != version {
+			t.Fatalf("expected %s=%s got=%q", name, version, got)
+		}
+	}
+	if got := versionOf(deps, "com.example:unresolved"); got != "" {
+		t.Fatalf("unresolved properties must not be reported, got %q", got)
+	}
+}
+
+func TestPomReader_Sources(t *testing.T) {
+	tmpDir := t.TempDir()
+	childDir := filepath.Join(tmpDir, "child")
+	if err := os.MkdirAll(childDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	parent := `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>1.0.0</version>
+    <relativePath>root/pom.xml</relativePath>
+  </parent>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+</project>`
+	child := `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>child</artifactId>
+</project>`
+	if err := os.WriteFile(filepath.Join(tmpDir, "pom.xml"), []byte(parent), 0644); err != nil {
+		t.Fatalf("write parent pom: %v", err)
+	}
+	fpath := filepath.Join(childDir, "pom.xml")
+	if err := os.WriteFile(fpath, []byte(child), 0644); err != nil {
+		t.Fatalf("write child pom: %v", err)
+	}
+
+	_, sources, err := NewPomReader().(SourceReader).ReadDependenciesAndSources(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependenciesAndSources returned error: %v", err)
+	}
+	// the parent and the grandparent it points at, which doesn't exist yet
+	want := []string{filepath.Join(tmpDir, "pom.xml"), filepath.Join(tmpDir, "root", "pom.xml")}
+	if !reflect.DeepEqual(sources, want) {
+		t.Fatalf("expected sources %v, got %v", want, sources)
 	}
 }
@@ -0,0 +1,254 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// normalizePythonName normalizes a distribution name as described in PEP 503,
+// so Requests_Toolbelt, requests.toolbelt and requests-toolbelt are equal.
+func normalizePythonName(name string) string {
+	return pythonNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// RequirementsReader reads pip requirements files (requirements*.txt). Only
+// exact pins (== and ===) are reported; ranges don't name an installed
+// version. Files included with -r are followed.
+type RequirementsReader struct{}
+
+func NewRequirementsReader() DependencyReader { return &RequirementsReader{} }
+
+func (r *RequirementsReader) Name() string { return "requirements.txt" }
+
+func (r *RequirementsReader) Patterns() []string { return []string{"requirements*.txt"} }
+
+func (r *RequirementsReader) Supports(filename string) bool {
+	return matchesAny(filename, r.Patterns())
+}
+
+// requirementComment matches a comment, which starts with # at the beginning
+// of a line or after whitespace (a # inside a URL is not a comment).
+var requirementComment = regexp.MustCompile(`(^|\s)#.*$`)
+
+// requirementPin matches `name[extras] == version` at the start of a
+// requirement specifier.
+var requirementPin = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(===|==)\s*([^\s;,\\]+)`)
+
+func (r *RequirementsReader) ReadDependencies(path string) ([]Dependency, error) {
+	deps, _, err := r.ReadDependenciesAndSources(path)
+	return deps, err
+}
+
+// ReadDependenciesAndSources also returns the files included with -r,
+// including missing ones.
+func (r *RequirementsReader) ReadDependenciesAndSources(path string) ([]Dependency, []string, error) {
+	visited := make(map[string]bool)
+	deps, err := readRequirements(path, filepath.Dir(path), visited)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deps, otherSources(path, visited), nil
+}
+
+// readRequirements parses one requirements file. Locations are the path of
+// the file declaring the pin, relative to the directory of the top-level file.
+func readRequirements(path, baseDir string, visited map[string]bool) ([]Dependency, error) {
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		if visited[abs] {
+			return nil, nil
+		}
+		visited[abs] = true
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	location, err := filepath.Rel(baseDir, path)
+	if err != nil {
+		location = filepath.Base(path)
+	}
+	location = filepath.ToSlash(location)
+
+	var deps []Dependency
+	for _, line := range requirementLines(data) {
+		if include, ok := requirementInclude(line); ok {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(path), include)
+			}
+			// a missing include must not hide the pins of this file
+			included, err := readRequirements(include, baseDir, visited)
+			if err != nil {
+				log.Printf("Warning: %s: skipping include %s: %v", path, include, err)
+				continue
+			}
+			deps = append(deps, included...)
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			continue // other options: -c, -e, --index-url, ...
+		}
+
+		m := requirementPin.FindStringSubmatch(line)
+		if m == nil || strings.Contains(m[3], "*") {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      normalizePythonName(m[1]),
+			Version:   m[3],
+			Ecosystem: EcosystemPyPI,
+			Location:  location,
+		})
+	}
+
+	return deps, nil
+}
+
+// requirementLines returns the logical lines of a requirements file with
+// continuations joined and comments removed.
+func requirementLines(data []byte) []string {
+	var lines []string
+	var cur strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(requirementComment.ReplaceAllString(scanner.Text(), ""))
+
+		if strings.HasSuffix(line, "\\") {
+			cur.WriteString(strings.TrimSuffix(line, "\\"))
+			cur.WriteString(" ")
+			continue
+		}
+		cur.WriteString(line)
+		if l := strings.TrimSpace(cur.String()); l != "" {
+			lines = append(lines, l)
+		}
+		cur.Reset()
+	}
+	if l := strings.TrimSpace(cur.String()); l != "" {
+		lines = append(lines, l)
+	}
+
+	return lines
+}
+
+// requirementInclude returns the file named by a -r/--requirement option.
+func requirementInclude(line string) (string, bool) {
+	for _, opt := range []string{"--requirement", "-r"} {
+		rest, ok := strings.CutPrefix(line, opt)
+		if !ok {
+			continue
+		}
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, "="))
+		if rest == "" {
+			return "", false
+		}
+		return strings.Fields(rest)[0], true
+	}
+	return "", false
+}
+
+// PoetryLockReader reads poetry.lock files.
+type PoetryLockReader struct{}
+
+func NewPoetryLockReader() DependencyReader { return &PoetryLockReader{} }
+
+func (r *PoetryLockReader) Name() string { return "poetry.lock" }
+
+func (r *PoetryLockReader) Supports(filename string) bool {
+	return filename == "poetry.lock"
+}
+
+func (r *PoetryLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	return readTOMLPackages(path, EcosystemPyPI, normalizePythonName, func(pkg map[string]any) bool { return true })
+}
+
+// UvLockReader reads uv.lock files.
+type UvLockReader struct{}
+
+func NewUvLockReader() DependencyReader { return &UvLockReader{} }
+
+func (r *UvLockReader) Name() string { return "uv.lock" }
+
+func (r *UvLockReader) Supports(filename string) bool {
+	return filename == "uv.lock"
+}
+
+func (r *UvLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	return readTOMLPackages(path, EcosystemPyPI, normalizePythonName, func(pkg map[string]any) bool {
+		// the project itself and workspace members are local sources
+		source, _ := pkg["source"].(map[string]any)
+		return source["editable"] == nil && source["virtual"] == nil
+	})
+}
+
+// PipfileLockReader reads Pipfile.lock files.
+type PipfileLockReader struct{}
+
+func NewPipfileLockReader() DependencyReader { return &PipfileLockReader{} }
+
+func (r *PipfileLockReader) Name() string { return "Pipfile.lock" }
+
+func (r *PipfileLockReader) Supports(filename string) bool {
+	return filename == "Pipfile.lock"
+}
+
+type pipfileLock struct {
+	Default map[string]pipfilePackage `json:"default"`
+	Develop map[string]pipfilePackage `json:"develop"`
+}
+
+type pipfilePackage struct {
+	Version string `json:"version"` // "==1.2.3"
+}
+
+func (r *PipfileLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var pl pipfileLock
+	if err := json.Unmarshal(data, &pl); err != nil {
+		return nil, fmt.Errorf("unmarshal Pipfile.lock: %w", err)
+	}
+
+	var deps []Dependency
+	for _, section := range []struct {
+		name     string
+		packages map[string]pipfilePackage
+	}{{"default", pl.Default}, {"develop", pl.Develop}} {
+		names := make([]string, 0, len(section.packages))
+		for name := range section.packages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			version := strings.TrimLeft(section.packages[name].Version, "=")
+			if version == "" {
+				continue // VCS and path dependencies
+			}
+			deps = append(deps, Dependency{
+				Name:      normalizePythonName(name),
+				Version:   version,
+				Ecosystem: EcosystemPyPI,
+				Location:  section.name + "." + name,
+			})
+		}
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,191 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequirementsReader_ReadDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "requirements"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	base := `# shared pins
+Requests_Toolbelt==1.0.0
+urllib3>=1.26  # ranges are not pins
+`
+	top := `-r requirements/base.txt
+--index-url https://pypi.org/simple
+requests[socks]==2.31.0 ; python_version >= "3.8" \
+    --hash=sha256:58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f
+django === 4.2.1
+flask==2.*
+git+https://github.com/org/repo.git#egg=repo
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "requirements", "base.txt"), []byte(base), 0644); err != nil {
+		t.Fatalf("write base requirements: %v", err)
+	}
+	fpath := filepath.Join(tmpDir, "requirements-dev.txt")
+	if err := os.WriteFile(fpath, []byte(top), 0644); err != nil {
+		t.Fatalf("write requirements: %v", err)
+	}
+
+	r := NewRequirementsReader()
+	if !r.Supports("requirements-dev.txt") || r.Supports("notes.txt") {
+		t.Fatalf("unexpected Supports result")
+	}
+	deps, err := r.ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"requests-toolbelt": "1.0.0",
+		"requests":          "2.31.0",
+		"django":            "4.2.1",
+	}
+	for name, version := range want {
+		if got := versionOf(deps, name); got != version {
+			t.Fatalf("expected %s=%s got=%q", name, version, got)
+		}
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("expected %d pins, got %d: %+v", len(want), len(deps), deps)
+	}
+	if d := occurrences(deps, "requests-toolbelt"); d[0].Location != "requirements/base.txt" || d[0].Ecosystem != EcosystemPyPI {
+		t.Fatalf("unexpected included pin %+v", d[0])
+	}
+}
+
+func TestRequirementsReader_MissingInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	fpath := filepath.Join(tmpDir, "requirements.txt")
+	content := `-r requirements/missing.txt
+requests==2.31.0
+`
+	if err := os.WriteFile(fpath, []byte(content), 0644); err != nil {
+		t.Fatalf("write requirements: %v", err)
+	}
+
+	deps, sources, err := NewRequirementsReader().(SourceReader).ReadDependenciesAndSources(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+	if len(deps) != 1 || versionOf(deps, "requests") != "2.31.0" {
+		t.Fatalf("expected the file's own pin despite the missing include, got %+v", deps)
+	}
+	// the include is a source, so creating it later triggers a rescan
+	if want := filepath.Join(tmpDir, "requirements", "missing.txt"); len(sources) != 1 || sources[0] != want {
+		t.Fatalf("expected the missing include as a source, got %v", sources)
+	}
+}
+
+func TestPoetryLockReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "poetry.lock")
+	data := `# This file is automatically @generated by Poetry 1.7.0 and should not be changed by hand.
+
+[[package]]
+name = "requests-toolbelt"
+version = "1.0.0"
+description = "A utility belt for advanced users of python-requests"
+optional = false
+python-versions = ">=2.7, !=3.0.*"
+files = [
+    {file = "requests-toolbelt-1.0.0.tar.gz", hash = "sha256:7681a0a3d047012b5bdc0ee37d7f8f07ebe76ab08caeccfc3921ce23c88d5bc6"},
+]
+
+[package.dependencies]
+requests = ">=2.0.1,<3.0.0"
+
+[[package]]
+name = "Typing_Extensions"
+version = "4.8.0"
+
+[metadata]
+lock-version = "2.0"
+`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write poetry.lock: %v", err)
+	}
+
+	deps, err := NewPoetryLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+	if got := versionOf(deps, "requests-toolbelt"); got != "1.0.0" {
+		t.Fatalf("expected requests-toolbelt=1.0.0 got=%q", got)
+	}
+	if got := versionOf(deps, "typing-extensions"); got != "4.8.0" {
+		t.Fatalf("expected normalized typing-extensions=4.8.0 got=%q", got)
+	}
+}
+
+func TestUvLockReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "uv.lock")
+	data := `version = 1
+requires-python = ">=3.12"
+
+[[package]]
+name = "app"
+version = "0.1.0"
+source = { editable = "." }
+dependencies = [
+    { name = "requests-toolbelt" },
+]
+
+[[package]]
+name = "requests-toolbelt"
+version = "1.0.0"
+source = { registry = "https://pypi.org/simple" }
+wheels = [
+    { url = "https://files.pythonhosted.org/x.whl", hash = "sha256:abc" },
+]
+`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write uv.lock: %v", err)
+	}
+
+	deps, err := NewUvLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+	if got := versionOf(deps, "requests-toolbelt"); got != "1.0.0" {
+		t.Fatalf("expected requests-toolbelt=1.0.0 got=%q", got)
+	}
+	if got := versionOf(deps, "app"); got != "" {
+		t.Fatalf("editable project must not be reported")
+	}
+}
+
+func TestPipfileLockReader_ReadDependencies(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "Pipfile.lock")
+	data := `{
+  "_meta": { "hash": { "sha256": "abc" } },
+  "default": {
+    "requests-toolbelt": { "hashes": ["sha256:abc"], "version": "==1.0.0" },
+    "local": { "path": "./local", "editable": true }
+  },
+  "develop": {
+    "Py_Test": { "version": "==7.4.0" }
+  }
+}`
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write Pipfile.lock: %v", err)
+	}
+
+	deps, err := NewPipfileLockReader().ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+	if got := versionOf(deps, "requests-toolbelt"); got != "1.0.0" {
+		t.Fatalf("expected requests-toolbelt=1.0.0 got=%q", got)
+	}
+	if got := versionOf(deps, "py-test"); got != "7.4.0" {
+		t.Fatalf("expected normalized py-test=7.4.0 got=%q", got)
+	}
+	if len(deps) != 2 {
+		t.Fatalf("expected 2 dependencies, got %d", len(deps))
+	}
+}
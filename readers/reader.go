@@ -1,7 +1,58 @@
 package readers
 
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Ecosystems a Dependency can belong to.
+const (
+	EcosystemNpm   = "npm"
+	EcosystemMaven = "maven"
+	EcosystemPyPI  = "pypi"
+	EcosystemGo    = "golang"
+	EcosystemCargo = "cargo"
+	EcosystemGem   = "gem"
+	// EcosystemComposer is Packagist, the PHP package registry.
+	EcosystemComposer = "composer"
+	EcosystemNuGet    = "nuget"
+)
+
+// NormalizeName returns the canonical form of a package name for comparisons
+// within an ecosystem: PyPI names are normalized as described in PEP 503 and
+// NuGet ids are case-insensitive. Other ecosystems compare names as written.
+func NormalizeName(ecosystem, name string) string {
+	switch ecosystem {
+	case EcosystemPyPI:
+		return normalizePythonName(name)
+	case EcosystemNuGet:
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// Dependency is a single occurrence of a package in a dependency file. The
+// same package may occur several times (e.g. hoisted and nested copies in a
+// package-lock.json) and every occurrence is reported separately.
+type Dependency struct {
+	// Name is the package name as bad package lists refer to it, e.g.
+	// "@scope/pkg" for npm or "groupId:artifactId" for Maven.
+	Name string
+	// Version is the exact resolved version.
+	Version string
+	// Ecosystem is the package ecosystem (EcosystemNpm, EcosystemMaven, ...).
+	Ecosystem string
+	// Location identifies where in the file the package was found, such as
+	// the package-lock.json "packages" key (node_modules/a/node_modules/b).
+	Location string
+	// Line is the 1-based line of the entry in the file, or 0 when the
+	// reader can't tell.
+	Line int
+}
+
 // DependencyReader is an interface for reading dependency files (package-lock.json, pom.xml, etc.)
-// Implementations must detect whether they support a filename and return a map of package->version.
+// Implementations must detect whether they support a filename and return every dependency they find.
 type DependencyReader interface {
 	// Name returns the reader's human-friendly name.
 	Name() string
@@ -9,6 +60,43 @@ type DependencyReader interface {
 	// Supports returns true if the reader can parse a file with the provided filename.
 	Supports(filename string) bool
 
-	// ReadDependencies reads the file at path and returns a map[pkg]version or an error.
-	ReadDependencies(path string) (map[string]string, error)
+	// ReadDependencies reads the file at path and returns all dependency occurrences or an error.
+	ReadDependencies(path string) ([]Dependency, error)
+}
+
+// SourceReader is implemented by readers whose results depend on files other
+// than the one they read, such as included requirements files. The scan state
+// records these sources so a change to any of them triggers a rescan.
+type SourceReader interface {
+	// ReadDependenciesAndSources is ReadDependencies that also returns the
+	// other files that were consulted, including ones that were looked for
+	// but don't exist.
+	ReadDependenciesAndSources(path string) ([]Dependency, []string, error)
+}
+
+// otherSources returns the sorted paths in visited other than path itself.
+func otherSources(path string, visited map[string]bool) []string {
+	self, err := filepath.Abs(path)
+	if err != nil {
+		self = path
+	}
+	var sources []string
+	for p := range visited {
+		if p != self {
+			sources = append(sources, p)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// TreeReader reads dependencies from an installed package tree (such as a
+// local Maven repository) rather than from a single dependency file.
+type TreeReader interface {
+	// Name returns the reader's human-friendly name.
+	Name() string
+
+	// ReadTree walks the tree rooted at root and returns every package found.
+	// Dependency locations are relative to root.
+	ReadTree(root string) ([]Dependency, error)
 }
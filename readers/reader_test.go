@@ -0,0 +1,33 @@
+package readers
+
+// versionOf returns the version of the first occurrence of name in deps, or
+// "" when the package is absent.
+func versionOf(deps []Dependency, name string) string {
+	for _, d := range deps {
+		if d.Name == name {
+			return d.Version
+		}
+	}
+	return ""
+}
+
+// lineOf returns the line of the first occurrence of name in deps.
+func lineOf(deps []Dependency, name string) int {
+	for _, d := range deps {
+		if d.Name == name {
+			return d.Line
+		}
+	}
+	return 0
+}
+
+// occurrences returns every occurrence of name in deps.
+func occurrences(deps []Dependency, name string) []Dependency {
+	var out []Dependency
+	for _, d := range deps {
+		if d.Name == name {
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package readers
+
+import "path/filepath"
+
+// PatternMatcher is implemented by readers that recognise files by glob
+// patterns (filepath.Match syntax, matched against the base name) such as
+// "*.csproj" rather than by a fixed file name.
+type PatternMatcher interface {
+	Patterns() []string
+}
+
+// Registry picks the reader responsible for a file.
+type Registry struct {
+	readers []DependencyReader
+}
+
+// NewRegistry returns a registry consulting rs in order.
+func NewRegistry(rs ...DependencyReader) *Registry {
+	return &Registry{readers: rs}
+}
+
+// Lookup returns the first reader that handles filename (a base name), or nil.
+// Readers implementing PatternMatcher are matched by their patterns, all
+// others by Supports.
+func (reg *Registry) Lookup(filename string) DependencyReader {
+	for _, r := range reg.readers {
+		if pm, ok := r.(PatternMatcher); ok {
+			if matchesAny(filename, pm.Patterns()) {
+				return r
+			}
+			continue
+		}
+		if r.Supports(filename) {
+			return r
+		}
+	}
+	return nil
+}
+
+// matchesAny reports whether filename matches one of the glob patterns.
+func matchesAny(filename string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, filename); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package readers
+
+import "testing"
+
+func TestRegistry_Lookup(t *testing.T) {
+	reg := NewRegistry(NewPackageLockReader(), NewRequirementsReader(), NewProjectFileReader())
+
+	cases := map[string]string{
+		"package-lock.json":    "package-lock.json",
+		"requirements-dev.txt": "requirements.txt",
+		"App.csproj":           "csproj",
+		"Lib.fsproj":           "csproj",
+		"notes.txt":            "",
+		"csproj":               "",
+	}
+	for filename, want := range cases {
+		r := reg.Lookup(filename)
+		got := ""
+		if r != nil {
+			got = r.Name()
+		}
+		if got != want {
+			t.Fatalf("Lookup(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package readers
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseTOML is a small TOML parser covering what lockfiles and version
+// catalogs use: tables, arrays of tables, dotted and quoted keys, strings
+// (basic, literal and multi-line), arrays and inline tables. Tables decode to
+// map[string]any, arrays to []any, booleans to bool and every other scalar
+// (numbers, dates) to its literal text.
+func parseTOML(data []byte) (map[string]any, error) {
+	p := &tomlParser{data: data}
+	root := make(map[string]any)
+	current := root
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			array := p.hasPrefix("[[")
+			if array {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			if !p.hasPrefix(closing) {
+				return nil, p.errorf("expected %q", closing)
+			}
+			p.pos += len(closing)
+			if current, err = openTOMLTable(root, keys, array); err != nil {
+				return nil, p.errorf("%v", err)
+			}
+		} else {
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if p.eof() || p.peek() != '=' {
+				return nil, p.errorf("expected '=' after key")
+			}
+			p.pos++
+			p.skipSpace()
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if err := setTOMLKey(current, keys, value); err != nil {
+				return nil, p.errorf("%v", err)
+			}
+		}
+
+		if err := p.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// openTOMLTable returns the table addressed by a [table] or [[array]] header,
+// creating it (and any missing parents) as needed.
+func openTOMLTable(root map[string]any, keys []string, array bool) (map[string]any, error) {
+	t := root
+	for _, k := range keys[:len(keys)-1] {
+		var err error
+		if t, err = descendTOML(t, k); err != nil {
+			return nil, err
+		}
+	}
+
+	last := keys[len(keys)-1]
+	if array {
+		existing, _ := t[last].([]any)
+		if t[last] != nil && existing == nil {
+			return nil, fmt.Errorf("key %q is not an array of tables", last)
+		}
+		table := make(map[string]any)
+		t[last] = append(existing, table)
+		return table, nil
+	}
+	return descendTOML(t, last)
+}
+
+// descendTOML returns the child table k of t. For arrays of tables the most
+// recently added element is used.
+func descendTOML(t map[string]any, k string) (map[string]any, error) {
+	switch v := t[k].(type) {
+	case nil:
+		child := make(map[string]any)
+		t[k] = child
+		return child, nil
+	case map[string]any:
+		return v, nil
+	case []any:
+		if len(v) > 0 {
+			if child, ok := v[len(v)-1].(map[string]any); ok {
+				return child, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("key %q is not a table", k)
+}
+
+func setTOMLKey(t map[string]any, keys []string, value any) error {
+	for _, k := range keys[:len(keys)-1] {
+		var err error
+		if t, err = descendTOML(t, k); err != nil {
+			return err
+		}
+	}
+	last := keys[len(keys)-1]
+	if _, exists := t[last]; exists {
+		return fmt.Errorf("duplicate key %q", last)
+	}
+	t[last] = value
+	return nil
+}
+
+type tomlParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *tomlParser) eof() bool { return p.pos >= len(p.data) }
+
+func (p *tomlParser) peek() byte { return p.data[p.pos] }
+
+func (p *tomlParser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.data[p.pos:], []byte(s))
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line := bytes.Count(p.data[:min(p.pos, len(p.data))], []byte("\n")) + 1
+	return fmt.Errorf("toml line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips spaces and tabs on the current line.
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	if !p.eof() && p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.pos++
+		}
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments.
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		if p.eof() || (p.peek() != '\n' && p.peek() != '\r') {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace()
+	p.skipComment()
+	if p.eof() {
+		return nil
+	}
+	if p.peek() == '\r' {
+		p.pos++
+	}
+	if p.eof() || p.peek() == '\n' {
+		return nil
+	}
+	return p.errorf("unexpected %q", p.peek())
+}
+
+// parseKey parses a possibly dotted key such as `a."b.c".d`.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil, p.errorf("expected key")
+		}
+
+		var key string
+		switch p.peek() {
+		case '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		case '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid key character %q", p.peek())
+			}
+			key = string(p.data[start:p.pos])
+		}
+		keys = append(keys, key)
+
+		p.skipSpace()
+		if p.eof() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	if p.eof() {
+		return nil, p.errorf("expected value")
+	}
+
+	switch p.peek() {
+	case '"':
+		if p.hasPrefix(`"""`) {
+			return p.parseMultilineString(`"""`, true)
+		}
+		return p.parseBasicString()
+	case '\'':
+		if p.hasPrefix("'''") {
+			return p.parseMultilineString("'''", false)
+		}
+		return p.parseLiteralString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(",]}#\r\n", rune(p.peek())) {
+		p.pos++
+	}
+	raw := strings.TrimSpace(string(p.data[start:p.pos]))
+	switch raw {
+	case "":
+		return nil, p.errorf("expected value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return raw, nil
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *tomlParser) parseEscape(b *strings.Builder) error {
+	p.pos++ // backslash
+	if p.eof() {
+		return p.errorf("unterminated escape")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.data) {
+			return p.errorf("short unicode escape")
+		}
+		code, err := strconv.ParseUint(string(p.data[p.pos:p.pos+n]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape")
+		}
+		b.WriteRune(rune(code))
+		p.pos += n
+	default:
+		return p.errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++ // opening quote
+	start := p.pos
+	for !p.eof() && p.peek() != '\'' {
+		if p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		p.pos++
+	}
+	if p.eof() {
+		return "", p.errorf("unterminated string")
+	}
+	s := string(p.data[start:p.pos])
+	p.pos++
+	return s, nil
+}
+
+func (p *tomlParser) parseMultilineString(delim string, escapes bool) (string, error) {
+	p.pos += len(delim)
+	// a newline immediately after the opening delimiter is trimmed
+	if p.hasPrefix("\r\n") {
+		p.pos += 2
+	} else if p.hasPrefix("\n") {
+		p.pos++
+	}
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		if p.hasPrefix(delim) {
+			p.pos += len(delim)
+			return b.String(), nil
+		}
+		if escapes && p.peek() == '\\' {
+			// line-ending backslash: skip the newline and leading whitespace
+			rest := bytes.TrimLeft(p.data[p.pos+1:], " \t")
+			if len(rest) > 0 && (rest[0] == '\n' || rest[0] == '\r') {
+				p.pos = len(p.data) - len(bytes.TrimLeft(rest, " \t\r\n"))
+				continue
+			}
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte(p.peek())
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.pos++ // [
+	values := []any{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		p.skipBlank()
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (map[string]any, error) {
+	p.pos++ // {
+	table := make(map[string]any)
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+
+		keys, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != '=' {
+			return nil, p.errorf("expected '=' in inline table")
+		}
+		p.pos++
+		p.skipSpace()
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := setTOMLKey(table, keys, v); err != nil {
+			return nil, p.errorf("%v", err)
+		}
+
+		p.skipSpace()
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+// tomlString returns t[key] if it is a string.
+func tomlString(t map[string]any, key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+// tomlTables returns the tables of an array of tables (or array of inline
+// tables) stored under key.
+func tomlTables(t map[string]any, key string) []map[string]any {
+	arr, _ := t[key].([]any)
+	var tables []map[string]any
+	for _, v := range arr {
+		if m, ok := v.(map[string]any); ok {
+			tables = append(tables, m)
+		}
+	}
+	return tables
+}
+
+// readTOMLPackages reports the name/version of every [[package]] table of a
+// TOML lockfile (poetry.lock, uv.lock, Cargo.lock) for which include returns
+// true. Names are passed through normalize unless it is nil.
+func readTOMLPackages(path, ecosystem string, normalize func(string) string, include func(pkg map[string]any) bool) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	doc, err := parseTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+
+	var deps []Dependency
+	for _, pkg := range tomlTables(doc, "package") {
+		name, version := tomlString(pkg, "name"), tomlString(pkg, "version")
+		if name == "" || version == "" || !include(pkg) {
+			continue
+		}
+		location := "package." + name
+		if normalize != nil {
+			name = normalize(name)
+		}
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   version,
+			Ecosystem: ecosystem,
+			Location:  location,
+		})
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,85 @@
+package readers
+
+import "testing"
+
+func TestParseTOML(t *testing.T) {
+	data := `# comment
+title = "catalog" # trailing comment
+literal = 'C:\path'
+
+[versions]
+guava = { strictly = "31.1-jre" }
+
+[libraries]
+guava.module = "com.google.guava:guava"
+guava.version.ref = "guava"
+"quoted.key" = "value"
+
+[[package]]
+name = "serde"
+version = "1.0.188"
+dependencies = [
+ "serde_derive",   # comment inside array
+]
+
+[[package]]
+name = "multi"
+description = """
+line one \
+  continued"""
+optional = true
+`
+
+	doc, err := parseTOML([]byte(data))
+	if err != nil {
+		t.Fatalf("parseTOML returned error: %v", err)
+	}
+
+	if got := tomlString(doc, "title"); got != "catalog" {
+		t.Fatalf("expected title=catalog got=%q", got)
+	}
+	if got := tomlString(doc, "literal"); got != `C:\path` {
+		t.Fatalf("expected literal string unchanged got=%q", got)
+	}
+
+	versions := doc["versions"].(map[string]any)
+	if got := tomlString(versions["guava"].(map[string]any), "strictly"); got != "31.1-jre" {
+		t.Fatalf("expected inline table value got=%q", got)
+	}
+
+	libraries := doc["libraries"].(map[string]any)
+	guava := libraries["guava"].(map[string]any)
+	if got := tomlString(guava["version"].(map[string]any), "ref"); got != "guava" {
+		t.Fatalf("expected dotted key value got=%q", got)
+	}
+	if got := tomlString(libraries, "quoted.key"); got != "value" {
+		t.Fatalf("expected quoted key value got=%q", got)
+	}
+
+	packages := tomlTables(doc, "package")
+	if len(packages) != 2 {
+		t.Fatalf("expected 2 [[package]] tables, got %d", len(packages))
+	}
+	if deps := packages[0]["dependencies"].([]any); len(deps) != 1 || deps[0] != "serde_derive" {
+		t.Fatalf("unexpected multi-line array: %v", deps)
+	}
+	if got := tomlString(packages[1], "description"); got != "line one continued" {
+		t.Fatalf("unexpected multi-line string %q", got)
+	}
+	if packages[1]["optional"] != true {
+		t.Fatalf("expected boolean value")
+	}
+}
+
+func TestParseTOML_Errors(t *testing.T) {
+	for _, data := range []string{
+		`key = "unterminated`,
+		`key = [1, 2`,
+		"a = 1\na = 2",
+		`[table`,
+	} {
+		if _, err := parseTOML([]byte(data)); err == nil {
+			t.Fatalf("expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// YarnLockReader reads yarn.lock files in both the classic (v1) text format
+// and the YAML based format written by Yarn 2+ (Berry).
+type YarnLockReader struct{}
+
+func NewYarnLockReader() DependencyReader { return &YarnLockReader{} }
+
+func (r *YarnLockReader) Name() string { return "yarn.lock" }
+
+func (r *YarnLockReader) Supports(filename string) bool {
+	return filename == "yarn.lock"
+}
+
+// yarnEntry is a single top-level block of a yarn.lock file.
+type yarnEntry struct {
+	header     string // descriptor list, e.g. `lodash@^4.17.4, lodash@^4.17.21`
+	line       int    // line of the header
+	version    string
+	resolution string // Berry only, e.g. `lodash@npm:4.17.21`
+}
+
+func (r *YarnLockReader) ReadDependencies(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	entries, err := parseYarnLock(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse yarn.lock: %w", err)
+	}
+
+	var deps []Dependency
+	for _, e := range entries {
+		if e.version == "" {
+			continue
+		}
+		name, ok := yarnEntryName(e)
+		if !ok {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   e.version,
+			Ecosystem: EcosystemNpm,
+			Location:  e.header,
+			Line:      e.line,
+		})
+	}
+
+	return deps, nil
+}
+
+// parseYarnLock splits a yarn.lock into its top-level entries. Only the
+// fields we need are kept; nested blocks such as "dependencies" are skipped.
+// The v1 format separates keys and values with a space (`version "1.0.0"`)
+// while Berry uses YAML (`version: 1.0.0`); both are accepted.
+func parseYarnLock(data []byte) ([]yarnEntry, error) {
+	var entries []yarnEntry
+	var cur *yarnEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == 0 {
+			if cur != nil {
+				entries = append(entries, *cur)
+				cur = nil
+			}
+			if !strings.HasSuffix(trimmed, ":") {
+				return nil, fmt.Errorf("unexpected top-level line %q", trimmed)
+			}
+			// descriptors never contain quotes, so dropping them handles
+			// both `"a@^1", "a@^2":` (v1) and `"a@npm:^1, a@npm:^2":` (Berry)
+			header := strings.ReplaceAll(strings.TrimSuffix(trimmed, ":"), `"`, "")
+			if header == "__metadata" {
+				continue
+			}
+			cur = &yarnEntry{header: header, line: lineNo}
+			continue
+		}
+
+		// only direct fields of an entry are interesting
+		if cur == nil || indent != 2 {
+			continue
+		}
+
+		key, value := splitYarnField(trimmed)
+		switch key {
+		case "version":
+			cur.version = value
+		case "resolution":
+			cur.resolution = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		entries = append(entries, *cur)
+	}
+
+	return entries, nil
+}
+
+// splitYarnField splits `key "value"` (v1) or `key: value` (Berry).
+func splitYarnField(s string) (string, string) {
+	sep := strings.IndexAny(s, " :")
+	if sep < 0 {
+		return s, ""
+	}
+	key := s[:sep]
+	value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s[sep:]), ":"))
+	return unquote(key), unquote(value)
+}
+
+// unquote strips one pair of surrounding single or double quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// yarnEntryName returns the real package name of an entry, resolving npm:
+// aliases and patch: protocols. Entries for workspaces and local links are
+// not registry packages and report ok=false.
+func yarnEntryName(e yarnEntry) (string, bool) {
+	if e.resolution != "" {
+		// Berry: the resolution always names the real package,
+		// e.g. `string-width@npm:4.2.3` for an alias or
+		// `resolve@patch:resolve@npm%3A1.22.1#...` for a patch.
+		name, ref := splitNpmDescriptor(e.resolution)
+		protocol, _, _ := strings.Cut(ref, ":")
+		switch protocol {
+		case "workspace", "link", "portal":
+			return "", false
+		}
+		return name, name != ""
+	}
+
+	// v1: derive the name from the first descriptor in the header
+	first, _, _ := strings.Cut(e.header, ",")
+	name, ref := splitNpmDescriptor(strings.TrimSpace(first))
+	if target, ok := strings.CutPrefix(ref, "npm:"); ok {
+		// alias: `string-width-cjs@npm:string-width@^4.2.0`
+		if aliased, aliasRange := splitNpmDescriptor(target); aliasRange != "" {
+			name = aliased
+		}
+	}
+	if strings.HasPrefix(ref, "workspace:") || strings.HasPrefix(ref, "link:") {
+		return "", false
+	}
+	return name, name != ""
+}
+
+// splitNpmDescriptor splits `name@range` into name and range, taking care of
+// scoped names (`@scope/name@range`).
+func splitNpmDescriptor(s string) (string, string) {
+	if len(s) == 0 {
+		return "", ""
+	}
+	at := strings.Index(s[1:], "@")
+	if at < 0 {
+		return s, ""
+	}
+	at++
+	return s[:at], s[at+1:]
+}
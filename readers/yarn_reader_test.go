@@ -0,0 +1,114 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestYarnLockReader_ReadDependencies_V1(t *testing.T) {
+	tmpDir := t.TempDir()
+	fpath := filepath.Join(tmpDir, "yarn.lock")
+
+	data := `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+  version "7.12.13"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.12.13.tgz"
+  dependencies:
+    "@babel/highlight" "^7.12.13"
+
+left-pad@^1.2.0, left-pad@^1.3.0:
+  version "1.3.0"
+
+"string-width-cjs@npm:string-width@^4.2.0":
+  version "4.2.3"
+`
+
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write tmp yarn.lock: %v", err)
+	}
+
+	r := NewYarnLockReader()
+	deps, err := r.ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := versionOf(deps, "@babel/code-frame"); got != "7.12.13" {
+		t.Fatalf("expected @babel/code-frame=7.12.13 got=%q", got)
+	}
+	if got := versionOf(deps, "left-pad"); got != "1.3.0" {
+		t.Fatalf("expected left-pad=1.3.0 got=%q", got)
+	}
+	if got := lineOf(deps, "left-pad"); got != 11 {
+		t.Fatalf("expected left-pad on line 11, got %d", got)
+	}
+	if got := versionOf(deps, "string-width"); got != "4.2.3" {
+		t.Fatalf("expected aliased string-width=4.2.3 got=%q", got)
+	}
+	if got := versionOf(deps, "@babel/highlight"); got != "" {
+		t.Fatalf("nested dependency ranges must not be reported, got %q", got)
+	}
+}
+
+func TestYarnLockReader_ReadDependencies_Berry(t *testing.T) {
+	tmpDir := t.TempDir()
+	fpath := filepath.Join(tmpDir, "yarn.lock")
+
+	data := `# This file is generated by running "yarn install" inside your project.
+
+__metadata:
+  version: 6
+  cacheKey: 8
+
+"@rxap/ngx-bootstrap@npm:^19.0.0":
+  version: 19.0.3
+  resolution: "@rxap/ngx-bootstrap@npm:19.0.3"
+  checksum: abc
+  languageName: node
+  linkType: hard
+
+"string-width-cjs@npm:string-width@^4.2.0":
+  version: 4.2.3
+  resolution: "string-width@npm:4.2.3"
+  dependencies:
+    emoji-regex: ^8.0.0
+
+"resolve@patch:resolve@^1.20.0#~builtin<compat/resolve>":
+  version: 1.22.1
+  resolution: "resolve@patch:resolve@npm%3A1.22.1#~builtin<compat/resolve>::version=1.22.1&hash=07638b"
+
+"my-app@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "my-app@workspace:."
+`
+
+	if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+		t.Fatalf("write tmp yarn.lock: %v", err)
+	}
+
+	r := NewYarnLockReader()
+	deps, err := r.ReadDependencies(fpath)
+	if err != nil {
+		t.Fatalf("ReadDependencies returned error: %v", err)
+	}
+
+	if got := versionOf(deps, "@rxap/ngx-bootstrap"); got != "19.0.3" {
+		t.Fatalf("expected @rxap/ngx-bootstrap=19.0.3 got=%q", got)
+	}
+	if got := versionOf(deps, "string-width"); got != "4.2.3" {
+		t.Fatalf("expected aliased string-width=4.2.3 got=%q", got)
+	}
+	if got := versionOf(deps, "resolve"); got != "1.22.1" {
+		t.Fatalf("expected patched resolve=1.22.1 got=%q", got)
+	}
+	if got := versionOf(deps, "my-app"); got != "" {
+		t.Fatalf("workspace entries must not be reported, got %q", got)
+	}
+	if len(deps) != 3 {
+		t.Fatalf("expected 3 dependencies, got %d: %+v", len(deps), deps)
+	}
+}
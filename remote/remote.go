@@ -0,0 +1,445 @@
+// Package remote downloads bad package lists from URLs into the lists
+// directory and keeps them up to date.
+package remote
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joelcma/dewormer/signature"
+)
+
+// DefaultRefresh is how often a list is checked when no refresh interval is
+// configured.
+const DefaultRefresh = 24 * time.Hour
+
+// maxListSize bounds a download so a misbehaving server can't fill the disk.
+const maxListSize = 512 << 20
+
+// maxSignatureSize bounds a signature download; minisign files are well
+// under 1 KiB.
+const maxSignatureSize = 64 << 10
+
+// List is a bad package list published at a URL, as configured under
+// remote_lists.
+type List struct {
+	URL string `json:"url"`
+	// Refresh is how often to check for a new version, as a Go duration
+	// ("6h", "30m"). Defaults to 24h.
+	Refresh string `json:"refresh,omitempty"`
+	// Format is the list format: txt, json, yaml or zip (OSV advisories).
+	// Defaults to the extension of the URL, or txt.
+	Format string `json:"format,omitempty"`
+	// Name is the file name in the lists directory. Defaults to the last
+	// element of the URL path followed by a hash of the URL.
+	Name string `json:"name,omitempty"`
+	// SignatureURL is a detached signature of the list (minisign or raw
+	// ed25519), stored next to it as <name>.minisig or <name>.sig.
+	SignatureURL string `json:"signature_url,omitempty"`
+}
+
+// formats maps list formats to the extension the list loader expects.
+var formats = map[string]string{
+	"txt":  ".txt",
+	"json": ".json",
+	"osv":  ".json",
+	"yaml": ".yaml",
+	"yml":  ".yaml",
+	"zip":  ".zip",
+}
+
+// FileName returns the name the list is stored under in the lists directory.
+// A name taken from the URL carries a hash of the whole URL, so lists with
+// the same base name on different hosts or paths don't overwrite each other.
+func (l List) FileName() (string, error) {
+	u, err := url.Parse(l.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", l.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported URL %q: only http and https are supported", l.URL)
+	}
+
+	name := l.Name
+	if name == "" {
+		name = path.Base(u.Path)
+		if name == "." || name == "/" {
+			name = u.Hostname()
+		}
+	}
+	name = filepath.Base(filepath.Clean(name))
+	if name == "." || name == ".." || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid list name %q", name)
+	}
+
+	format, err := l.format(name)
+	if err != nil {
+		return "", err
+	}
+	ext := formats[format]
+	if !strings.EqualFold(filepath.Ext(name), ext) {
+		name += ext
+	}
+	if l.Name == "" {
+		stem := strings.TrimSuffix(name, name[len(name)-len(ext):])
+		name = stem + "-" + urlHash(l.URL) + name[len(stem):]
+	}
+	return name, nil
+}
+
+// urlHash returns a short hash of a list URL.
+func urlHash(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:4])
+}
+
+func (l List) format(name string) (string, error) {
+	if l.Format != "" {
+		format := strings.ToLower(l.Format)
+		if _, ok := formats[format]; !ok {
+			return "", fmt.Errorf("unknown format %q", l.Format)
+		}
+		return format, nil
+	}
+	if format := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), "."); formats[format] != "" {
+		return format, nil
+	}
+	return "txt", nil
+}
+
+// signatureName returns the file name the list's signature is stored under.
+func (l List) signatureName(name string) string {
+	if strings.HasSuffix(strings.ToLower(l.SignatureURL), ".minisig") {
+		return name + ".minisig"
+	}
+	return name + ".sig"
+}
+
+func (l List) refresh() (time.Duration, error) {
+	if l.Refresh == "" {
+		return DefaultRefresh, nil
+	}
+	d, err := time.ParseDuration(l.Refresh)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid refresh interval %q", l.Refresh)
+	}
+	return d, nil
+}
+
+// ListState is what is remembered about a remote list between runs.
+type ListState struct {
+	File         string `json:"file"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// CheckedAt is when the server last confirmed the copy is current
+	// (a download or a 304), in UnixNano.
+	CheckedAt int64 `json:"checked_at"`
+}
+
+// State maps list URLs to their state. It is stored outside the lists
+// directory, which must only contain lists.
+type State map[string]ListState
+
+func loadState(path string) State {
+	state := make(State)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.Printf("Ignoring unreadable remote list state %s: %v", path, err)
+		return make(State)
+	}
+	return state
+}
+
+func saveState(path string, state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Fetcher downloads remote lists into Dir.
+type Fetcher struct {
+	Client *http.Client
+	// Dir is the lists directory the lists are written to.
+	Dir string
+	// StatePath is the file caching validators and check times.
+	StatePath string
+	// Now returns the current time; tests replace it.
+	Now func() time.Time
+	// Verifier, if set, checks lists that have a signature_url. A download
+	// whose signature doesn't verify doesn't replace the last good copy.
+	Verifier *signature.Verifier
+}
+
+func NewFetcher(dir, statePath string) *Fetcher {
+	return &Fetcher{
+		Client:    &http.Client{Timeout: 2 * time.Minute},
+		Dir:       dir,
+		StatePath: statePath,
+		Now:       time.Now,
+	}
+}
+
+// Refresh brings every list that is due for a check up to date. A list whose
+// download fails keeps its last good copy, and its age is logged.
+func (f *Fetcher) Refresh(lists []List) {
+	if err := os.MkdirAll(f.Dir, 0755); err != nil {
+		log.Printf("Could not create lists directory %s: %v", f.Dir, err)
+		return
+	}
+
+	state := loadState(f.StatePath)
+	var replaced []string
+	for _, l := range lists {
+		previous := state[l.URL].File
+		st, err := f.refreshList(l, state[l.URL])
+		if err != nil {
+			f.logFailure(l, st, err)
+		}
+		if previous != "" && previous != st.File {
+			if err != nil {
+				continue // keep the old copy until the new one is downloaded
+			}
+			replaced = append(replaced, previous)
+		}
+		if st.File != "" {
+			state[l.URL] = st
+		}
+	}
+	f.removeReplaced(replaced, state)
+
+	if err := saveState(f.StatePath, state); err != nil {
+		log.Printf("Failed to save remote list state: %v", err)
+	}
+}
+
+// refreshList checks one list. It returns the updated state, which is left
+// unchanged on error.
+func (f *Fetcher) refreshList(l List, st ListState) (ListState, error) {
+	name, err := l.FileName()
+	if err != nil {
+		return st, err
+	}
+	refresh, err := l.refresh()
+	if err != nil {
+		return st, err
+	}
+
+	dest := filepath.Join(f.Dir, name)
+	_, statErr := os.Stat(dest)
+	haveCopy := statErr == nil && st.File == name
+	if l.SignatureURL != "" {
+		// fetch the list again if its signature is missing
+		_, sigErr := os.Stat(filepath.Join(f.Dir, l.signatureName(name)))
+		haveCopy = haveCopy && sigErr == nil
+	}
+	if !haveCopy {
+		st = ListState{File: name}
+	}
+	if haveCopy && f.Now().Sub(time.Unix(0, st.CheckedAt)) < refresh {
+		return st, nil // not due yet
+	}
+
+	req, err := http.NewRequest(http.MethodGet, l.URL, nil)
+	if err != nil {
+		return st, err
+	}
+	if haveCopy {
+		if st.ETag != "" {
+			req.Header.Set("If-None-Match", st.ETag)
+		}
+		if st.LastModified != "" {
+			req.Header.Set("If-Modified-Since", st.LastModified)
+		}
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return st, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && haveCopy:
+		st.CheckedAt = f.Now().UnixNano()
+		log.Printf("Remote list %s is up to date", l.URL)
+		return st, nil
+	case resp.StatusCode != http.StatusOK:
+		return st, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxListSize+1))
+	if err != nil {
+		return st, fmt.Errorf("read response: %w", err)
+	}
+	if len(data) > maxListSize {
+		return st, fmt.Errorf("list is larger than %d bytes", maxListSize)
+	}
+	format, _ := l.format(name)
+	if err := validate(format, data); err != nil {
+		return st, err
+	}
+
+	var sig []byte
+	if l.SignatureURL != "" {
+		if sig, err = f.fetch(l.SignatureURL, maxSignatureSize); err != nil {
+			return st, fmt.Errorf("download signature: %w", err)
+		}
+		if f.Verifier != nil {
+			if err := f.Verifier.Verify(data, sig); err != nil {
+				return st, fmt.Errorf("invalid signature: %w", err)
+			}
+		}
+	}
+
+	// write both files before installing either, so a failure can't leave
+	// a new list next to an old signature
+	tmpList, err := stageFile(dest, data)
+	if err != nil {
+		return st, err
+	}
+	defer os.Remove(tmpList)
+	sigName := l.signatureName(name)
+	var tmpSig string
+	if sig != nil {
+		if tmpSig, err = stageFile(filepath.Join(f.Dir, sigName), sig); err != nil {
+			return st, err
+		}
+		defer os.Remove(tmpSig)
+	}
+
+	if err := os.Rename(tmpList, dest); err != nil {
+		return st, err
+	}
+	if sig != nil {
+		if err := os.Rename(tmpSig, filepath.Join(f.Dir, sigName)); err != nil {
+			// the old signature doesn't match the new list; fetch both again
+			os.Remove(dest)
+			return st, err
+		}
+		// drop a signature stored under the other extension, which would
+		// otherwise be checked first
+		for _, ext := range signature.Extensions {
+			if name+ext != sigName {
+				os.Remove(filepath.Join(f.Dir, name+ext))
+			}
+		}
+	}
+
+	log.Printf("Downloaded remote list %s to %s (%d bytes)", l.URL, dest, len(data))
+	return ListState{
+		File:         name,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		CheckedAt:    f.Now().UnixNano(),
+	}, nil
+}
+
+// fetch downloads a small file such as a signature.
+func (f *Fetcher) fetch(rawURL string, limit int64) ([]byte, error) {
+	resp, err := f.Client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("larger than %d bytes", limit)
+	}
+	return data, nil
+}
+
+func (f *Fetcher) logFailure(l List, st ListState, err error) {
+	if st.CheckedAt == 0 {
+		log.Printf("Warning: could not download remote list %s: %v", l.URL, err)
+		return
+	}
+	age := f.Now().Sub(time.Unix(0, st.CheckedAt)).Round(time.Minute)
+	log.Printf("Warning: could not refresh remote list %s: %v; using the copy last confirmed %s ago", l.URL, err, age)
+}
+
+// validate rejects responses that can't be the expected list, such as an
+// HTML error page served with status 200, so they don't replace a good copy.
+func validate(format string, data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return fmt.Errorf("downloaded list is empty")
+	case format == "zip":
+		if _, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+			return fmt.Errorf("downloaded list is not a zip archive: %w", err)
+		}
+	case format == "json" || format == "osv":
+		if !json.Valid(data) {
+			return fmt.Errorf("downloaded list is not valid JSON")
+		}
+	case trimmed[0] == '<':
+		return fmt.Errorf("downloaded list looks like HTML")
+	}
+	return nil
+}
+
+// stageFile writes data to a hidden temporary file next to path, which the
+// list loader skips, and returns its name. Renaming it over path replaces
+// path atomically, so a failed write never leaves a truncated list behind.
+func stageFile(path string, data []byte) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// removeReplaced deletes copies a list no longer uses, such as one stored
+// under an older naming scheme, which would otherwise still be loaded. A
+// file another list now uses is kept.
+func (f *Fetcher) removeReplaced(files []string, state State) {
+	inUse := make(map[string]bool)
+	for _, st := range state {
+		inUse[st.File] = true
+	}
+	for _, name := range files {
+		if inUse[name] {
+			continue
+		}
+		for _, suffix := range append([]string{""}, signature.Extensions...) {
+			os.Remove(filepath.Join(f.Dir, name+suffix))
+		}
+		log.Printf("Removed %s, which is no longer used by a remote list", name)
+	}
+}
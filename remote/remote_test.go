@@ -0,0 +1,286 @@
+package remote
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joelcma/dewormer/signature"
+)
+
+// listServer serves body with an ETag and counts requests, answering
+// conditional requests with 304 and failing when status is set.
+type listServer struct {
+	body     string
+	etag     string
+	status   int
+	requests int
+	lastINM  string
+}
+
+func (s *listServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests++
+	s.lastINM = r.Header.Get("If-None-Match")
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+	if s.lastINM == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", s.etag)
+	w.Header().Set("Last-Modified", "Thu, 28 Nov 2024 10:00:00 GMT")
+	w.Write([]byte(s.body))
+}
+
+func newTestFetcher(t *testing.T, now *time.Time) (*Fetcher, string) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "bad_package_lists")
+	f := NewFetcher(dir, filepath.Join(tmpDir, "remote_lists.json"))
+	f.Now = func() time.Time { return *now }
+	return f, dir
+}
+
+// listPath returns where l is stored in dir.
+func listPath(t *testing.T, dir string, l List) string {
+	t.Helper()
+	name, err := l.FileName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, name)
+}
+
+func TestRefresh_CachesAndRevalidates(t *testing.T) {
+	srv := &listServer{body: "evil@1.0.0\n", etag: `"v1"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	now := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	f, dir := newTestFetcher(t, &now)
+	lists := []List{{URL: ts.URL + "/lists/community.txt", Refresh: "6h"}}
+
+	f.Refresh(lists)
+	dest := listPath(t, dir, lists[0])
+	data, err := os.ReadFile(dest)
+	if err != nil || string(data) != "evil@1.0.0\n" {
+		t.Fatalf("expected list to be downloaded, got %q, %v", data, err)
+	}
+
+	// not due yet: no request
+	now = now.Add(time.Hour)
+	f.Refresh(lists)
+	if srv.requests != 1 {
+		t.Fatalf("expected no request before the refresh interval, got %d requests", srv.requests)
+	}
+
+	// due: conditional request answered with 304
+	now = now.Add(6 * time.Hour)
+	f.Refresh(lists)
+	if srv.requests != 2 || srv.lastINM != `"v1"` {
+		t.Fatalf("expected a conditional request, got %d requests with If-None-Match %q", srv.requests, srv.lastINM)
+	}
+	state := loadState(f.StatePath)
+	if got := time.Unix(0, state[lists[0].URL].CheckedAt); !got.Equal(now) {
+		t.Fatalf("expected 304 to count as a check, got %v", got)
+	}
+
+	// changed upstream
+	srv.body, srv.etag = "evil@1.0.0\nworse@2.0.0\n", `"v2"`
+	now = now.Add(7 * time.Hour)
+	f.Refresh(lists)
+	if data, _ := os.ReadFile(dest); string(data) != srv.body {
+		t.Fatalf("expected updated list, got %q", data)
+	}
+}
+
+func TestRefresh_KeepsLastGoodCopy(t *testing.T) {
+	srv := &listServer{body: `{"entries": []}`, etag: `"v1"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	now := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	f, dir := newTestFetcher(t, &now)
+	lists := []List{{URL: ts.URL + "/feed", Format: "json", Name: "team"}}
+
+	f.Refresh(lists)
+	dest := filepath.Join(dir, "team.json")
+	if data, err := os.ReadFile(dest); err != nil || string(data) != srv.body {
+		t.Fatalf("expected list to be downloaded as team.json, got %q, %v", data, err)
+	}
+	checked := loadState(f.StatePath)[lists[0].URL].CheckedAt
+
+	// server error
+	srv.status = http.StatusInternalServerError
+	now = now.Add(25 * time.Hour)
+	f.Refresh(lists)
+
+	// error page served with 200
+	srv.status = 0
+	srv.body, srv.etag = "<html>maintenance</html>", `"v2"`
+	now = now.Add(25 * time.Hour)
+	f.Refresh(lists)
+
+	if data, _ := os.ReadFile(dest); string(data) != `{"entries": []}` {
+		t.Fatalf("expected last good copy to be kept, got %q", data)
+	}
+	if got := loadState(f.StatePath)[lists[0].URL].CheckedAt; got != checked {
+		t.Fatalf("expected failed refreshes not to update the check time")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected only the list in the lists directory, got %d entries", len(entries))
+	}
+}
+
+func TestRefresh_VerifiesSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	srv := &listServer{body: "evil@1.0.0\n", etag: `"v1"`}
+	sig := ed25519.Sign(priv, []byte(srv.body))
+	mux := http.NewServeMux()
+	mux.Handle("/npm.txt", srv)
+	mux.HandleFunc("/npm.txt.sig", func(w http.ResponseWriter, r *http.Request) { w.Write(sig) })
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	now := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	f, dir := newTestFetcher(t, &now)
+	f.Verifier = signature.NewVerifier([]signature.PublicKey{{Key: pub}})
+	lists := []List{{URL: ts.URL + "/npm.txt", SignatureURL: ts.URL + "/npm.txt.sig", Refresh: "1h"}}
+
+	f.Refresh(lists)
+	dest := listPath(t, dir, lists[0])
+	if status, err := f.Verifier.VerifyFile(dest); status != signature.StatusVerified {
+		t.Fatalf("expected a verified list and signature, got %s (%v)", status, err)
+	}
+
+	// a new version whose signature doesn't match is not installed
+	srv.body, srv.etag = "tampered@1.0.0\n", `"v2"`
+	now = now.Add(2 * time.Hour)
+	f.Refresh(lists)
+	data, _ := os.ReadFile(dest)
+	if string(data) != "evil@1.0.0\n" {
+		t.Fatalf("expected the last good copy to be kept, got %q", data)
+	}
+
+	// a signed version that can't be installed next to its signature
+	// doesn't leave the new list paired with the old signature
+	sig = ed25519.Sign(priv, []byte(srv.body))
+	sigPath := dest + ".sig"
+	os.Remove(sigPath)
+	if err := os.MkdirAll(filepath.Join(sigPath, "blocked"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	f.Refresh(lists)
+	if data, err := os.ReadFile(dest); err == nil && string(data) == srv.body {
+		t.Fatalf("expected the new list not to be installed without its signature")
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Fatalf("expected temporary files to be removed, found %s", e.Name())
+		}
+	}
+
+	// once the signature can be written, both are installed
+	os.RemoveAll(sigPath)
+	f.Refresh(lists)
+	if status, err := f.Verifier.VerifyFile(dest); status != signature.StatusVerified {
+		t.Fatalf("expected the new list and signature to be installed, got %s (%v)", status, err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != srv.body {
+		t.Fatalf("expected the new list, got %q", data)
+	}
+}
+
+func TestRefresh_SameBaseName(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/a/list.txt", &listServer{body: "evil@1.0.0\n", etag: `"a"`})
+	mux.Handle("/b/list.txt", &listServer{body: "worse@2.0.0\n", etag: `"b"`})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	now := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	f, dir := newTestFetcher(t, &now)
+	lists := []List{{URL: ts.URL + "/a/list.txt"}, {URL: ts.URL + "/b/list.txt"}}
+
+	f.Refresh(lists)
+	for i, want := range []string{"evil@1.0.0\n", "worse@2.0.0\n"} {
+		if data, _ := os.ReadFile(listPath(t, dir, lists[i])); string(data) != want {
+			t.Fatalf("expected %s to be stored separately, got %q", lists[i].URL, data)
+		}
+	}
+}
+
+func TestRefresh_RemovesRenamedCopy(t *testing.T) {
+	srv := &listServer{body: "evil@1.0.0\n", etag: `"v1"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	now := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	f, dir := newTestFetcher(t, &now)
+	lists := []List{{URL: ts.URL + "/npm.txt"}}
+
+	// a copy stored under the name used before URL hashes
+	old := filepath.Join(dir, "npm.txt")
+	os.MkdirAll(dir, 0755)
+	if err := os.WriteFile(old, []byte("stale@1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveState(f.StatePath, State{lists[0].URL: {File: "npm.txt", CheckedAt: now.UnixNano()}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the old copy is kept while the new one can't be downloaded
+	srv.status = http.StatusInternalServerError
+	f.Refresh(lists)
+	if _, err := os.Stat(old); err != nil {
+		t.Fatalf("expected the old copy to be kept after a failed download: %v", err)
+	}
+
+	srv.status = 0
+	f.Refresh(lists)
+	if data, _ := os.ReadFile(listPath(t, dir, lists[0])); string(data) != srv.body {
+		t.Fatalf("expected the list under its new name, got %q", data)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("expected the old copy to be removed, got %v", err)
+	}
+}
+
+func TestListFileName(t *testing.T) {
+	tests := []struct {
+		list List
+		want string
+	}{
+		{List{URL: "https://example.com/lists/npm.txt"}, "npm-28a46726.txt"},
+		{List{URL: "https://example.com/osv/all.zip"}, "all-a724711b.zip"},
+		{List{URL: "https://example.com/feed", Format: "yaml"}, "feed-92236c4e.yaml"},
+		{List{URL: "https://example.com/", Format: "osv"}, "example.com-0f115db0.json"},
+		{List{URL: "https://example.com/feed", Name: "community", Format: "yaml"}, "community.yaml"},
+		{List{URL: "https://example.com/x.json", Name: "../../etc/passwd"}, "passwd.txt"},
+	}
+	for _, tt := range tests {
+		got, err := tt.list.FileName()
+		if err != nil {
+			t.Fatalf("FileName(%+v): %v", tt.list, err)
+		}
+		if got != tt.want {
+			t.Fatalf("FileName(%+v) = %q, want %q", tt.list, got, tt.want)
+		}
+	}
+
+	for _, l := range []List{{URL: "file:///etc/passwd"}, {URL: "https://example.com/x", Format: "xml"}} {
+		if _, err := l.FileName(); err == nil {
+			t.Fatalf("expected error for %+v", l)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joelcma/dewormer/badlists"
+	"github.com/joelcma/dewormer/signature"
+)
+
+// ReportVersion is the version of the JSON report format. Fields may be
+// added without changing it; it is bumped when a field is removed or changes
+// meaning.
+const ReportVersion = 1
+
+// Output formats accepted by --format.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Report is the machine-readable result of a scan, written by --format json
+// and converted to SARIF by --format sarif.
+type Report struct {
+	ReportVersion int    `json:"report_version"`
+	Tool          string `json:"tool"`
+	// Version is the dewormer build version.
+	Version    string    `json:"version"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	ScanPaths  []string  `json:"scan_paths"`
+	// MavenRepositories are the local Maven repositories that were checked.
+	MavenRepositories []string `json:"maven_repositories,omitempty"`
+	// Lists are the bad package lists that were loaded.
+	Lists       []ReportList `json:"lists"`
+	BadPackages int          `json:"bad_packages"`
+	// ListErrors are lists that could not be loaded or were refused.
+	ListErrors []ListError `json:"list_errors"`
+	// FilesScanned counts files read this run; FilesSkipped counts files
+	// unchanged since the last scan whose cached findings were reported.
+	FilesScanned int           `json:"files_scanned"`
+	FilesSkipped int           `json:"files_skipped"`
+	ReaderErrors []ReaderError `json:"reader_errors"`
+	Findings     []Finding     `json:"findings"`
+
+	// results are the findings as scanned, for the text output.
+	results []ScanResult
+}
+
+// ReportList is a loaded bad package list.
+type ReportList struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Signature is the signature status of the list when signatures are
+	// checked: verified, unsigned or invalid.
+	Signature string `json:"signature,omitempty"`
+}
+
+// ListError is a bad package list that could not be used.
+type ListError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// ReaderError is a dependency file that could not be read.
+type ReaderError struct {
+	File   string `json:"file"`
+	Reader string `json:"reader"`
+	Error  string `json:"error"`
+}
+
+// Finding is a bad package found in a dependency file.
+type Finding struct {
+	Package       string   `json:"package"`
+	Version       string   `json:"version"`
+	Ecosystem     string   `json:"ecosystem,omitempty"`
+	File          string   `json:"file"`
+	Location      string   `json:"location,omitempty"`
+	Line          int      `json:"line,omitempty"`
+	List          string   `json:"list"`
+	ListSignature string   `json:"list_signature,omitempty"`
+	Advisory      string   `json:"advisory,omitempty"`
+	Summary       string   `json:"summary,omitempty"`
+	Severity      string   `json:"severity,omitempty"`
+	Reason        string   `json:"reason,omitempty"`
+	References    []string `json:"references,omitempty"`
+	Added         string   `json:"added,omitempty"`
+}
+
+func newReport(config *Config, startTime time.Time) *Report {
+	return &Report{
+		ReportVersion:     ReportVersion,
+		Tool:              "dewormer",
+		Version:           Version,
+		StartedAt:         startTime,
+		ScanPaths:         append([]string{}, config.ScanPaths...),
+		MavenRepositories: config.MavenRepositories,
+		Lists:             []ReportList{},
+		ListErrors:        []ListError{},
+		ReaderErrors:      []ReaderError{},
+		Findings:          []Finding{},
+	}
+}
+
+// addLists records the loaded lists with their signature status, which is
+// keyed by cleaned path.
+func (r *Report) addLists(listPaths []string, signatures map[string]signature.Status) {
+	for _, p := range listPaths {
+		name := filepath.Base(filepath.Clean(p))
+		r.Lists = append(r.Lists, ReportList{Name: name, Path: p, Signature: string(signatures[filepath.Clean(p)])})
+	}
+}
+
+func (r *Report) addListErrors(errs []badlists.ListError) {
+	for _, e := range errs {
+		r.ListErrors = append(r.ListErrors, ListError{Path: e.Path, Error: e.Err.Error()})
+	}
+}
+
+func (r *Report) addReaderError(file, reader string, err error) {
+	r.ReaderErrors = append(r.ReaderErrors, ReaderError{File: file, Reader: reader, Error: err.Error()})
+}
+
+// finish records the findings and end time of the scan.
+func (r *Report) finish(results []ScanResult) {
+	r.FinishedAt = time.Now()
+	r.results = results
+	for _, res := range results {
+		r.Findings = append(r.Findings, Finding{
+			Package:       res.Package,
+			Version:       res.Version,
+			Ecosystem:     res.Ecosystem,
+			File:          res.File,
+			Location:      res.Location,
+			Line:          res.Line,
+			List:          res.List,
+			ListSignature: string(res.ListSignature),
+			Advisory:      res.Advisory,
+			Summary:       res.Summary,
+			Severity:      res.Severity,
+			Reason:        res.Reason,
+			References:    res.References,
+			Added:         res.Added,
+		})
+	}
+}
+
+// writeText writes the findings of a report as text, one per line followed
+// by their details, and a summary line.
+func writeText(w io.Writer, r *Report) error {
+	var b strings.Builder
+	for _, result := range r.results {
+		file := result.File
+		if result.Line > 0 {
+			file += ":" + strconv.Itoa(result.Line)
+		}
+		fmt.Fprintf(&b, "%s@%s in %s%s (matched: %s)\n", result.Package, result.Version, file, formatLocation(result.Location), formatMatch(result))
+		for _, detail := range resultDetails(result) {
+			fmt.Fprintf(&b, "    %s\n", detail)
+		}
+	}
+
+	files := r.FilesScanned + r.FilesSkipped
+	if len(r.results) == 0 {
+		fmt.Fprintf(&b, "No threats found in %d files.\n", files)
+	} else {
+		fmt.Fprintf(&b, "Found %d infected dependencies in %d files.\n", len(r.results), files)
+	}
+	if n := len(r.ReaderErrors); n > 0 {
+		fmt.Fprintf(&b, "%d files could not be read; see the log for details.\n", n)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeReport writes the report in the given format to path, or to stdout
+// when path is empty. The text format is the log output, so nothing is
+// written.
+func writeReport(r *Report, format, path string) error {
+	var doc any
+	switch format {
+	case FormatText:
+		return nil
+	case FormatJSON:
+		doc = r
+	case FormatSARIF:
+		doc = sarifReport(r)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteReport_JSON(t *testing.T) {
+	start := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	report := newReport(&Config{ScanPaths: []string{"/projects"}}, start)
+	report.addLists([]string{"/lists/npm.txt"}, nil)
+	report.finish([]ScanResult{{
+		Package: "evil-pkg", Version: "1.0.0", Ecosystem: "npm", File: "/projects/a/package-lock.json",
+		List: "npm.txt", Advisory: "MAL-2024-1", Severity: "critical", References: []string{"https://example.com"},
+	}})
+
+	out := filepath.Join(t.TempDir(), "report.json")
+	if err := writeReport(report, FormatJSON, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if doc["report_version"] != float64(ReportVersion) || doc["tool"] != "dewormer" || doc["started_at"] != "2024-11-28T12:00:00Z" {
+		t.Fatalf("unexpected metadata: %v", doc)
+	}
+	// empty collections are arrays, not null, so consumers can iterate
+	if errs, ok := doc["reader_errors"].([]any); !ok || len(errs) != 0 {
+		t.Fatalf("expected an empty reader_errors array, got %v", doc["reader_errors"])
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	want := Finding{
+		Package: "evil-pkg", Version: "1.0.0", Ecosystem: "npm", File: "/projects/a/package-lock.json",
+		List: "npm.txt", Advisory: "MAL-2024-1", Severity: "critical", References: []string{"https://example.com"},
+	}
+	if len(decoded.Findings) != 1 || !reflect.DeepEqual(decoded.Findings[0], want) {
+		t.Fatalf("findings = %+v, want %+v", decoded.Findings, want)
+	}
+	if !reflect.DeepEqual(decoded.Lists, []ReportList{{Name: "npm.txt", Path: "/lists/npm.txt"}}) {
+		t.Fatalf("unexpected lists: %+v", decoded.Lists)
+	}
+}
+
+func TestRunScan_Report(t *testing.T) {
+	tmpDir := t.TempDir()
+	listsDir := filepath.Join(tmpDir, "lists")
+	project := filepath.Join(tmpDir, "project")
+	for _, dir := range []string{listsDir, project} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(listsDir, "npm.txt"):                 "pkg:npm/evil-pkg@1.0.0\n",
+		filepath.Join(project, "go.mod"):                   "module example.com/clean\n\ngo 1.22\n",
+		filepath.Join(project, "sub", "package-lock.json"): "{not json",
+	}
+	for p, content := range files {
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func(cfg, lists string) { ConfigPathOverride, BadListsDirOverride = cfg, lists }(ConfigPathOverride, BadListsDirOverride)
+	ConfigPathOverride = filepath.Join(tmpDir, "config.json")
+	BadListsDirOverride = listsDir
+
+	report := runScan(&Config{ScanPaths: []string{project}}, scanOptions{})
+	if report.BadPackages != 1 || len(report.Lists) != 1 || report.Lists[0].Name != "npm.txt" {
+		t.Fatalf("unexpected lists: %d packages from %+v", report.BadPackages, report.Lists)
+	}
+	if report.FilesScanned != 2 || len(report.Findings) != 0 {
+		t.Fatalf("expected 2 clean files scanned, got %d files and %+v", report.FilesScanned, report.Findings)
+	}
+	if len(report.ReaderErrors) != 1 || report.ReaderErrors[0].File != filepath.Join(project, "sub", "package-lock.json") {
+		t.Fatalf("expected the broken lockfile as a reader error, got %+v", report.ReaderErrors)
+	}
+	if report.FinishedAt.Before(report.StartedAt) {
+		t.Fatalf("finished %v before starting %v", report.FinishedAt, report.StartedAt)
+	}
+
+	// the second run skips the unchanged go.mod; the broken lockfile is retried
+	report = runScan(&Config{ScanPaths: []string{project}}, scanOptions{})
+	if report.FilesSkipped != 1 || report.FilesScanned != 1 {
+		t.Fatalf("expected 1 skipped and 1 scanned file, got %d and %d", report.FilesSkipped, report.FilesScanned)
+	}
+}
+
+func TestRunScan_IncludedFileChanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	listsDir := filepath.Join(tmpDir, "lists")
+	project := filepath.Join(tmpDir, "project")
+	base := filepath.Join(project, "requirements", "base.txt")
+	for _, dir := range []string{listsDir, filepath.Dir(base)} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(listsDir, "pypi.txt"):        "pkg:pypi/evil@1.0.0\n",
+		filepath.Join(project, "requirements.txt"): "-r requirements/base.txt\n",
+		base: "requests==2.31.0\n",
+	}
+	for p, content := range files {
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func(cfg, lists string) { ConfigPathOverride, BadListsDirOverride = cfg, lists }(ConfigPathOverride, BadListsDirOverride)
+	ConfigPathOverride = filepath.Join(tmpDir, "config.json")
+	BadListsDirOverride = listsDir
+
+	report := runScan(&Config{ScanPaths: []string{project}}, scanOptions{})
+	if report.FilesScanned != 1 || len(report.Findings) != 0 {
+		t.Fatalf("expected a clean first scan, got %d files and %+v", report.FilesScanned, report.Findings)
+	}
+
+	// only the included file changes; requirements.txt itself is untouched
+	if err := os.WriteFile(base, []byte("requests==2.31.0\nevil==1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(base, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	report = runScan(&Config{ScanPaths: []string{project}}, scanOptions{})
+	if report.FilesScanned != 1 || len(report.Findings) != 1 || report.Findings[0].Package != "evil" {
+		t.Fatalf("expected the changed include to be rescanned, got %d scanned and %+v", report.FilesScanned, report.Findings)
+	}
+
+	report = runScan(&Config{ScanPaths: []string{project}}, scanOptions{})
+	if report.FilesSkipped != 1 || len(report.Findings) != 1 {
+		t.Fatalf("expected the unchanged file to be skipped with its finding, got %d skipped and %+v", report.FilesSkipped, report.Findings)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FormatSARIF is the --format for SARIF 2.1.0, the format code-scanning
+// dashboards import.
+const FormatSARIF = "sarif"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver sarifDriver `json:"driver"`
+	} `json:"tool"`
+	// OriginalURIBaseIDs maps the SCANPATH<n> bases of result locations to
+	// the scan paths.
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Invocations        []sarifInvocation                `json:"invocations"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	Name                 string            `json:"name,omitempty"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	FullDescription      *sarifMessage     `json:"fullDescription,omitempty"`
+	HelpURI              string            `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifRuleConfig   `json:"defaultConfiguration"`
+	Properties           sarifRuleProperty `json:"properties"`
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperty struct {
+	Tags []string `json:"tags"`
+	// SecuritySeverity is the 0-10 score GitHub code scanning ranks
+	// security alerts by.
+	SecuritySeverity string `json:"security-severity,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	StartTimeUTC               string              `json:"startTimeUtc"`
+	EndTimeUTC                 string              `json:"endTimeUtc"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifReport converts a report to a SARIF log with a single run. Each
+// advisory, or each listed package of a list without advisories, is a rule.
+func sarifReport(r *Report) sarifLog {
+	run := sarifRun{
+		Invocations: []sarifInvocation{{
+			ExecutionSuccessful: len(r.ReaderErrors) == 0 && len(r.ListErrors) == 0,
+			StartTimeUTC:        r.StartedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			EndTimeUTC:          r.FinishedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}},
+		Results: []sarifResult{},
+	}
+	run.Tool.Driver = sarifDriver{
+		Name:           "dewormer",
+		Version:        r.Version,
+		InformationURI: "https://github.com/joelcma/dewormer",
+		Rules:          []sarifRule{},
+	}
+
+	bases := make([]string, len(r.ScanPaths))
+	for i, p := range r.ScanPaths {
+		if abs, err := filepath.Abs(p); err == nil {
+			// a file scanned on its own is located relative to its directory
+			if info, err := os.Stat(abs); err == nil && info.Mode().IsRegular() {
+				abs = filepath.Dir(abs)
+			}
+			bases[i] = abs
+			if run.OriginalURIBaseIDs == nil {
+				run.OriginalURIBaseIDs = make(map[string]sarifArtifactLocation)
+			}
+			run.OriginalURIBaseIDs[sarifBaseID(i)] = sarifArtifactLocation{URI: fileURI(abs) + "/"}
+		}
+	}
+
+	// a list that wasn't loaded means packages on it went unchecked
+	for _, e := range r.ListErrors {
+		run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications, sarifNotification{
+			Level:     "error",
+			Message:   sarifMessage{Text: "could not load bad package list: " + e.Error},
+			Locations: []sarifLocation{sarifFileLocation(e.Path, 0, bases)},
+		})
+	}
+	for _, e := range r.ReaderErrors {
+		run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications, sarifNotification{
+			Level:     "warning",
+			Message:   sarifMessage{Text: fmt.Sprintf("could not read dependencies with %s: %s", e.Reader, e.Error)},
+			Locations: []sarifLocation{sarifFileLocation(e.File, 0, bases)},
+		})
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, f := range r.Findings {
+		id := sarifRuleID(f)
+		idx, ok := ruleIndex[id]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[id] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleFor(id, f))
+		}
+
+		props := map[string]any{"package": f.Package, "version": f.Version, "list": f.List}
+		if f.Ecosystem != "" {
+			props["ecosystem"] = f.Ecosystem
+		}
+		if f.Location != "" {
+			props["location"] = f.Location
+		}
+		if f.ListSignature != "" {
+			props["list_signature"] = f.ListSignature
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:     id,
+			RuleIndex:  idx,
+			Level:      sarifLevel(f.Severity),
+			Message:    sarifMessage{Text: sarifResultMessage(f)},
+			Locations:  []sarifLocation{sarifFileLocation(f.File, f.Line, bases)},
+			Properties: props,
+		})
+	}
+
+	return sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}
+}
+
+// sarifRuleID identifies what a finding was matched by: its advisory, or
+// the list and package.
+func sarifRuleID(f Finding) string {
+	if f.Advisory != "" {
+		return f.Advisory
+	}
+	name := f.Package
+	if f.Ecosystem != "" {
+		name = f.Ecosystem + "/" + name
+	}
+	return f.List + "/" + name
+}
+
+func sarifRuleFor(id string, f Finding) sarifRule {
+	rule := sarifRule{
+		ID:                   id,
+		ShortDescription:     sarifMessage{Text: fmt.Sprintf("Known bad package %s (%s)", f.Package, f.List)},
+		DefaultConfiguration: sarifRuleConfig{Level: sarifLevel(f.Severity)},
+		Properties: sarifRuleProperty{
+			Tags:             []string{"security", "supply-chain"},
+			SecuritySeverity: sarifSecuritySeverity(f.Severity),
+		},
+	}
+	if f.Advisory != "" && f.Summary != "" {
+		rule.ShortDescription.Text = f.Summary
+	}
+	if desc := firstNonEmpty(f.Reason, f.Summary); desc != "" {
+		rule.FullDescription = &sarifMessage{Text: desc}
+	}
+	if len(f.References) > 0 {
+		rule.HelpURI = f.References[0]
+	}
+	return rule
+}
+
+func sarifResultMessage(f Finding) string {
+	msg := fmt.Sprintf("%s@%s is listed in %s", f.Package, f.Version, f.List)
+	if f.Advisory != "" {
+		msg += " (" + f.Advisory + ")"
+	}
+	if desc := firstNonEmpty(f.Reason, f.Summary); desc != "" {
+		msg += ": " + strings.TrimSuffix(desc, ".")
+	}
+	return msg + "."
+}
+
+// sarifLevel maps a severity to a SARIF level. Entries without a severity
+// are known-malicious packages and are errors.
+func sarifLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "low":
+		return "note"
+	case "moderate", "medium":
+		return "warning"
+	}
+	return "error"
+}
+
+func sarifSecuritySeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "low":
+		return "3.0"
+	case "moderate", "medium":
+		return "5.5"
+	case "high":
+		return "8.0"
+	}
+	return "9.5"
+}
+
+// sarifFileLocation locates a file relative to the scan path that contains
+// it, so dashboards can map it to the repository; other files get an
+// absolute file URI.
+func sarifFileLocation(path string, line int, bases []string) sarifLocation {
+	loc := sarifLocation{}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+
+	loc.PhysicalLocation.ArtifactLocation.URI = fileURI(abs)
+	for i, base := range bases {
+		if base == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(base, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			loc.PhysicalLocation.ArtifactLocation = sarifArtifactLocation{
+				URI:       (&url.URL{Path: filepath.ToSlash(rel)}).String(),
+				URIBaseID: sarifBaseID(i),
+			}
+			break
+		}
+	}
+
+	if line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+	return loc
+}
+
+func sarifBaseID(i int) string {
+	return fmt.Sprintf("SCANPATH%d", i)
+}
+
+func fileURI(abs string) string {
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive letter
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
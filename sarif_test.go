@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joelcma/dewormer/badlists"
+)
+
+func TestSarifReport(t *testing.T) {
+	root := t.TempDir()
+	start := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	report := newReport(&Config{ScanPaths: []string{root}}, start)
+	report.addReaderError(filepath.Join(root, "old", "yarn.lock"), "yarn.lock", os.ErrInvalid)
+	report.finish([]ScanResult{
+		{Package: "evil-pkg", Version: "1.0.0", Ecosystem: "npm", File: filepath.Join(root, "app", "yarn.lock"), Line: 12, List: "npm.txt", Reason: "Exfiltrates npm tokens."},
+		{Package: "evil-pkg", Version: "1.0.0", Ecosystem: "npm", File: filepath.Join(root, "web", "package-lock.json"), List: "npm.txt"},
+		{Package: "meh", Version: "2.0.0", Ecosystem: "pypi", File: "/elsewhere/requirements.txt", List: "osv", Advisory: "MAL-2024-1", Summary: "Malicious code in meh", Severity: "low", References: []string{"https://osv.dev/MAL-2024-1"}},
+	})
+
+	log := sarifReport(report)
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log: %+v", log)
+	}
+	run := log.Runs[0]
+
+	// one rule per list entry or advisory
+	rules := run.Tool.Driver.Rules
+	if len(rules) != 2 || rules[0].ID != "npm.txt/npm/evil-pkg" || rules[1].ID != "MAL-2024-1" {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+	if rules[1].HelpURI != "https://osv.dev/MAL-2024-1" || rules[1].DefaultConfiguration.Level != "note" {
+		t.Fatalf("unexpected advisory rule: %+v", rules[1])
+	}
+
+	if len(run.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(run.Results))
+	}
+	first := run.Results[0]
+	if first.RuleIndex != 0 || first.Level != "error" || first.Message.Text != "evil-pkg@1.0.0 is listed in npm.txt: Exfiltrates npm tokens." {
+		t.Fatalf("unexpected result: %+v", first)
+	}
+	loc := first.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "app/yarn.lock" || loc.ArtifactLocation.URIBaseID != "SCANPATH0" || loc.Region == nil || loc.Region.StartLine != 12 {
+		t.Fatalf("unexpected location: %+v", loc)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Fatal("expected no region when the line is unknown")
+	}
+	if uri := run.Results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "file:///elsewhere/requirements.txt" {
+		t.Fatalf("expected an absolute URI outside the scan paths, got %q", uri)
+	}
+
+	notes := run.Invocations[0].ToolExecutionNotifications
+	if len(notes) != 1 || notes[0].Locations[0].PhysicalLocation.ArtifactLocation.URI != "old/yarn.lock" {
+		t.Fatalf("expected the reader error as a notification, got %+v", notes)
+	}
+	if run.Invocations[0].ExecutionSuccessful {
+		t.Fatal("expected a run with reader errors not to be successful")
+	}
+
+	out := filepath.Join(t.TempDir(), "dewormer.sarif")
+	if err := writeReport(report, FormatSARIF, out); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(out)
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil || doc["$schema"] != sarifSchema {
+		t.Fatalf("expected a SARIF document, got %v (%v)", doc["$schema"], err)
+	}
+}
+
+func TestSarifReport_ListErrors(t *testing.T) {
+	start := time.Date(2024, 11, 28, 12, 0, 0, 0, time.UTC)
+	report := newReport(&Config{ScanPaths: []string{t.TempDir()}}, start)
+	report.finish(nil)
+	if inv := sarifReport(report).Runs[0].Invocations[0]; !inv.ExecutionSuccessful || len(inv.ToolExecutionNotifications) != 0 {
+		t.Fatalf("expected a clean run to be successful, got %+v", inv)
+	}
+
+	report.addListErrors([]badlists.ListError{{Path: "/lists/npm.txt", Err: errors.New("not signed")}})
+	inv := sarifReport(report).Runs[0].Invocations[0]
+	if inv.ExecutionSuccessful {
+		t.Fatal("expected a run with list errors not to be successful")
+	}
+	notes := inv.ToolExecutionNotifications
+	if len(notes) != 1 || notes[0].Level != "error" || notes[0].Message.Text != "could not load bad package list: not signed" ||
+		notes[0].Locations[0].PhysicalLocation.ArtifactLocation.URI != "file:///lists/npm.txt" {
+		t.Fatalf("expected the list error as a notification, got %+v", notes)
+	}
+}
+
+func TestSarifReport_FileScanPath(t *testing.T) {
+	dir := t.TempDir()
+	lockfile := filepath.Join(dir, "package-lock.json")
+	if err := os.WriteFile(lockfile, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report := newReport(&Config{ScanPaths: []string{lockfile}}, time.Now())
+	report.finish([]ScanResult{{Package: "evil-pkg", Version: "1.0.0", Ecosystem: "npm", File: lockfile, List: "npm.txt"}})
+
+	run := sarifReport(report).Runs[0]
+	if base := run.OriginalURIBaseIDs["SCANPATH0"].URI; base != fileURI(dir)+"/" {
+		t.Fatalf("expected the file's directory as base, got %q", base)
+	}
+	loc := run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation
+	if loc.URI != "package-lock.json" || loc.URIBaseID != "SCANPATH0" {
+		t.Fatalf("expected the file name relative to its directory, got %+v", loc)
+	}
+}
@@ -1,10 +1,19 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/joelcma/dewormer/badlists"
+	"github.com/joelcma/dewormer/readers"
+	"github.com/joelcma/dewormer/signature"
+	statepkg "github.com/joelcma/dewormer/state"
 )
 
 func TestShouldScan_Behavior(t *testing.T) {
@@ -19,36 +28,286 @@ func TestShouldScan_Behavior(t *testing.T) {
 		t.Fatalf("stat: %v", err)
 	}
 
+	const fp = "lists"
+	current := func(scannedAt time.Time) statepkg.FileState {
+		return statepkg.FileState{ScannedAt: scannedAt.UnixNano(), Reader: "package-lock.json", Version: statepkg.FormatVersion, Lists: fp}
+	}
+
 	// Case 1: no last-scan entry -> should scan
-	state := map[string]int64{}
-	_, last, need := shouldScan(fpath, info, time.Time{}, state, false)
+	state := statepkg.ScanState{}
+	_, last, need := shouldScan(fpath, info, time.Time{}, fp, state, false)
 	if !need {
 		t.Fatalf("expected needScan when lastScan missing, got need=%v last=%v", need, last)
 	}
 
 	// Case 2: lastScan after file modification -> do not scan
 	later := time.Now().Add(time.Hour)
-	state = map[string]int64{}
+	state = statepkg.ScanState{}
 	abs := filepath.Clean(fpath)
-	state[abs] = later.UnixNano()
-	_, last2, need2 := shouldScan(fpath, info, time.Time{}, state, false)
+	state[abs] = current(later)
+	_, last2, need2 := shouldScan(fpath, info, time.Time{}, fp, state, false)
 	if need2 {
 		t.Fatalf("expected skip when lastScan after file mod, got need=%v last=%v", need2, last2)
 	}
 
 	// Case 3: lastScan older than latestListMod -> should scan
 	old := time.Now().Add(-time.Hour)
-	state = map[string]int64{abs: old.UnixNano()}
+	state = statepkg.ScanState{abs: current(old)}
 	latestListMod := time.Now()
-	_, last3, need3 := shouldScan(fpath, info, latestListMod, state, false)
+	_, last3, need3 := shouldScan(fpath, info, latestListMod, fp, state, false)
 	if !need3 {
 		t.Fatalf("expected needScan when list mod is newer, got need=%v last=%v", need3, last3)
 	}
 
 	// Case 4: force rescan ignores persisted state
-	state = map[string]int64{abs: later.UnixNano()}
-	_, last4, need4 := shouldScan(fpath, info, time.Time{}, state, true)
+	state = statepkg.ScanState{abs: current(later)}
+	_, last4, need4 := shouldScan(fpath, info, time.Time{}, fp, state, true)
 	if !need4 {
 		t.Fatalf("expected force rescan to ignore scan state, got need=%v last=%v", need4, last4)
 	}
+
+	// Case 5: a legacy record holds only a timestamp -> should scan
+	state = statepkg.ScanState{abs: {ScannedAt: later.UnixNano()}}
+	if _, _, need := shouldScan(fpath, info, time.Time{}, fp, state, false); !need {
+		t.Fatalf("expected legacy record to be rescanned")
+	}
+
+	// Case 6: the lists changed since the record was made -> should scan
+	state = statepkg.ScanState{abs: current(later)}
+	if _, _, need := shouldScan(fpath, info, time.Time{}, "other lists", state, false); !need {
+		t.Fatalf("expected record made against other lists to be rescanned")
+	}
+}
+
+func TestListsFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "npm.txt")
+	if err := os.WriteFile(list, []byte("voip-callkit@1.0.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "old.txt")
+	if err := os.WriteFile(other, []byte("left-pad@1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fp := listsFingerprint([]string{list}, "off", "")
+	if fp != listsFingerprint([]string{list}, "off", "") {
+		t.Fatalf("expected fingerprint to be stable")
+	}
+	if fp == listsFingerprint([]string{other}, "off", "") {
+		t.Fatalf("expected different lists to change the fingerprint")
+	}
+	if fp == listsFingerprint([]string{list}, "require", "") {
+		t.Fatalf("expected signature policy to change the fingerprint")
+	}
+	if fp == listsFingerprint([]string{list}, "off", "high") {
+		t.Fatalf("expected advisory severity to change the fingerprint")
+	}
+	if err := os.WriteFile(list, []byte("voip-callkit@1.0.2\nleft-pad@1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if fp == listsFingerprint([]string{list}, "off", "") {
+		t.Fatalf("expected a changed list to change the fingerprint")
+	}
+}
+
+func TestCachedFindings_RoundTrip(t *testing.T) {
+	results := []ScanResult{{Package: "voip-callkit", Version: "1.0.2", Ecosystem: readers.EcosystemNpm, File: "/old/path", Location: "node_modules/voip-callkit", Line: 42, List: "osv", Advisory: "MAL-2024-1", Summary: "Malicious code in voip-callkit (npm)"}}
+
+	fs := statepkg.FileState{ScannedAt: time.Now().UnixNano(), Findings: findingsForState(results)}
+	cached := resultsFromState("/proj/package-lock.json", fs)
+
+	if len(cached) != 1 {
+		t.Fatalf("expected 1 cached result, got %d", len(cached))
+	}
+	want := ScanResult{Package: "voip-callkit", Version: "1.0.2", Ecosystem: readers.EcosystemNpm, File: "/proj/package-lock.json", Location: "node_modules/voip-callkit", Line: 42, List: "osv", Advisory: "MAL-2024-1", Summary: "Malicious code in voip-callkit (npm)"}
+	if !reflect.DeepEqual(cached[0], want) {
+		t.Fatalf("unexpected cached result: %+v", cached[0])
+	}
+}
+
+func TestFindMatches_EveryOccurrence(t *testing.T) {
+	deps := []readers.Dependency{
+		{Name: "chalk", Version: "4.1.2", Ecosystem: readers.EcosystemNpm, Location: "node_modules/chalk"},
+		{Name: "chalk", Version: "5.6.1", Ecosystem: readers.EcosystemNpm, Location: "node_modules/foo/node_modules/chalk"},
+	}
+	bad := badlists.NewSet()
+	bad.Add(badlists.Entry{Ecosystem: readers.EcosystemNpm, Name: "chalk", Version: "5.6.1", List: "npm.txt"})
+
+	results := findMatches(deps, bad, "/proj/package-lock.json")
+	if len(results) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(results))
+	}
+	if results[0].Location != "node_modules/foo/node_modules/chalk" {
+		t.Fatalf("expected nested install path, got %q", results[0].Location)
+	}
+}
+
+func TestMergeInstalled_NoDoubleReporting(t *testing.T) {
+	project := filepath.Join("/proj", "app")
+	results := []ScanResult{
+		{Package: "chalk", Version: "5.6.1", File: filepath.Join(project, "yarn.lock"), Location: "chalk@^5.0.0", List: "npm.txt"},
+	}
+	installed := []ScanResult{
+		// same install as the yarn.lock entry
+		{Package: "chalk", Version: "5.6.1", File: project, Location: "node_modules/chalk", List: "npm.txt"},
+		// only visible in node_modules (e.g. installed without a lockfile)
+		{Package: "debug", Version: "4.4.2", File: project, Location: "node_modules/debug", List: "npm.txt"},
+	}
+
+	merged := mergeInstalled(results, installed)
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 results after merge, got %d: %+v", len(merged), merged)
+	}
+	if merged[1].Package != "debug" {
+		t.Fatalf("expected installed-only package to be reported, got %+v", merged[1])
+	}
+}
+
+func TestMergeInstalled_SecondInstallPath(t *testing.T) {
+	project := filepath.Join("/proj", "app")
+	results := []ScanResult{
+		{Package: "chalk", Version: "5.6.1", File: filepath.Join(project, "package-lock.json"), Location: "node_modules/chalk", List: "npm.txt"},
+	}
+	installed := []ScanResult{
+		// same install as the package-lock.json entry
+		{Package: "chalk", Version: "5.6.1", File: project, Location: "node_modules/chalk", List: "npm.txt"},
+		// the same version installed again where the lockfile doesn't know it
+		{Package: "chalk", Version: "5.6.1", File: project, Location: "node_modules/cli/node_modules/chalk", List: "npm.txt"},
+	}
+
+	merged := mergeInstalled(results, installed)
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 results after merge, got %d: %+v", len(merged), merged)
+	}
+	if merged[1].Location != "node_modules/cli/node_modules/chalk" {
+		t.Fatalf("expected second install path to be reported, got %+v", merged[1])
+	}
+}
+
+func TestNotificationMessage_MostSevere(t *testing.T) {
+	plain := []ScanResult{{Package: "voip-callkit", Version: "1.0.2", List: "npm.txt"}}
+	if got := notificationMessage(plain); got != "Found 1 infected dependencies! Check logs for details." {
+		t.Fatalf("unexpected message for plain list: %q", got)
+	}
+
+	results := []ScanResult{
+		{Package: "meh", Version: "1.0.0", Severity: "low", Reason: "Unmaintained"},
+		{Package: "evil-pkg", Version: "2.0.5", Severity: "critical", Reason: "Exfiltrates npm tokens."},
+		{Package: "voip-callkit", Version: "1.0.2"},
+	}
+	want := "Found 3 infected dependencies! evil-pkg@2.0.5 (critical): Exfiltrates npm tokens. Check logs for details."
+	if got := notificationMessage(results); got != want {
+		t.Fatalf("notificationMessage = %q, want %q", got, want)
+	}
+}
+
+func TestVerifyLists_Policy(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	verifier := signature.NewVerifier([]signature.PublicKey{{Key: pub}})
+
+	tmpDir := t.TempDir()
+	write := func(name string, data []byte) string {
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	signed := write("signed.txt", []byte("evil@1.0.0\n"))
+	write("signed.txt.sig", ed25519.Sign(priv, []byte("evil@1.0.0\n")))
+	unsigned := write("unsigned.txt", []byte("evil@1.0.0\n"))
+	invalid := write("invalid.txt", []byte("evil@1.0.0\n"))
+	write("invalid.txt.sig", ed25519.Sign(priv, []byte("other@1.0.0\n")))
+	lists := []string{signed, unsigned, invalid}
+
+	kept, data, statuses, refused := verifyLists(lists, verifier, signature.PolicyWarn)
+	if !reflect.DeepEqual(kept, lists) || len(refused) != 0 {
+		t.Fatalf("warn should keep every list, got %v", kept)
+	}
+	want := map[string]signature.Status{
+		signed:   signature.StatusVerified,
+		unsigned: signature.StatusUnsigned,
+		invalid:  signature.StatusInvalid,
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+
+	kept, data, _, refused = verifyLists(lists, verifier, signature.PolicyRequire)
+	if !reflect.DeepEqual(kept, []string{signed}) || len(refused) != 2 || refused[0].Path != unsigned {
+		t.Fatalf("require should keep only the signed list, got %v (refused %v)", kept, refused)
+	}
+	// a list replaced after its signature was checked is loaded as verified
+	write("signed.txt", []byte("other@1.0.0\n"))
+	set, _ := badlists.LoadData(kept, data)
+	if len(set.MatchName("evil", "1.0.0")) != 1 || len(set.MatchName("other", "1.0.0")) != 0 {
+		t.Fatalf("expected the verified contents to be loaded")
+	}
+
+	if kept, _, statuses, _ := verifyLists(lists, verifier, signature.PolicyOff); len(kept) != 3 || len(statuses) != 0 {
+		t.Fatalf("off should keep every list unchecked, got %v %v", kept, statuses)
+	}
+}
+
+func TestRunScan_SignatureBySameNameLists(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	tmpDir := t.TempDir()
+	listsDir := filepath.Join(tmpDir, "lists")
+	other := filepath.Join(tmpDir, "team", "npm.txt")
+	project := filepath.Join(tmpDir, "project")
+	body := []byte("pkg:npm/evil@1.0.0\n")
+	files := map[string][]byte{
+		filepath.Join(listsDir, "npm.txt"):     body,
+		filepath.Join(listsDir, "npm.txt.sig"): ed25519.Sign(priv, body),
+		other:                                  []byte("pkg:npm/worse@1.0.0\n"),
+		filepath.Join(project, "package-lock.json"): []byte(`{"packages": {
+			"node_modules/evil": {"version": "1.0.0"},
+			"node_modules/worse": {"version": "1.0.0"}
+		}}`),
+	}
+	for p, content := range files {
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func(cfg, lists string) { ConfigPathOverride, BadListsDirOverride = cfg, lists }(ConfigPathOverride, BadListsDirOverride)
+	ConfigPathOverride = filepath.Join(tmpDir, "config.json")
+	BadListsDirOverride = listsDir
+
+	config := &Config{
+		ScanPaths:       []string{project},
+		BadPackageLists: []string{other},
+		TrustedKeys:     []string{base64.StdEncoding.EncodeToString(pub)},
+		SignaturePolicy: "warn",
+	}
+	want := map[string]string{
+		filepath.Join(listsDir, "npm.txt"): "verified",
+		other:                              "unsigned",
+		"evil":                             "verified",
+		"worse":                            "unsigned",
+	}
+	// the second run reports the findings recorded in the scan state
+	for run := 1; run <= 2; run++ {
+		report := runScan(config, scanOptions{})
+		got := make(map[string]string)
+		for _, l := range report.Lists {
+			got[l.Path] = l.Signature
+		}
+		for _, f := range report.Findings {
+			got[f.Package] = f.ListSignature
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: signature statuses = %v, want %v", run, got, want)
+		}
+	}
+}
+
+func TestFormatMatch_Signature(t *testing.T) {
+	r := ScanResult{List: "npm.txt", Severity: "high", ListSignature: signature.StatusVerified}
+	if got, want := formatMatch(r), "npm.txt (signature verified), severity: high"; got != want {
+		t.Fatalf("formatMatch = %q, want %q", got, want)
+	}
 }
@@ -0,0 +1,239 @@
+// Package signature verifies detached ed25519 signatures on bad package
+// lists, in minisign format or as a raw signature.
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Status is the outcome of checking a list's signature.
+type Status string
+
+const (
+	StatusVerified Status = "verified"
+	StatusUnsigned Status = "unsigned"
+	StatusInvalid  Status = "invalid"
+)
+
+// Policy decides what happens to lists that aren't verified.
+type Policy string
+
+const (
+	// PolicyOff loads every list without checking signatures.
+	PolicyOff Policy = "off"
+	// PolicyWarn loads every list and warns about unsigned or invalid ones.
+	PolicyWarn Policy = "warn"
+	// PolicyRequire refuses lists without a valid signature.
+	PolicyRequire Policy = "require"
+)
+
+// ParsePolicy parses the signature_policy setting. An empty setting means
+// warn when trusted keys are configured and off otherwise.
+func ParsePolicy(s string, haveKeys bool) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		if haveKeys {
+			return PolicyWarn, nil
+		}
+		return PolicyOff, nil
+	case PolicyOff, PolicyWarn, PolicyRequire:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown signature policy %q (use off, warn or require)", s)
+}
+
+// Extensions are the signature files looked for next to a list, in order:
+// list.txt.minisig, then list.txt.sig.
+var Extensions = []string{".minisig", ".sig"}
+
+// IsSignatureFile reports whether name is a signature rather than a list.
+func IsSignatureFile(name string) bool {
+	for _, ext := range Extensions {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicKey is a trusted ed25519 key. Keys from minisign carry a key ID that
+// signatures name, so only the matching key is tried.
+type PublicKey struct {
+	Key   ed25519.PublicKey
+	ID    [8]byte
+	hasID bool
+}
+
+// ParsePublicKey parses a base64 key: a minisign public key (the contents of
+// a .pub file, or just its second line) or a raw 32-byte ed25519 key.
+func ParsePublicKey(s string) (PublicKey, error) {
+	line := lastLine(s)
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("public key is not valid base64: %w", err)
+	}
+
+	switch {
+	case len(raw) == ed25519.PublicKeySize:
+		return PublicKey{Key: ed25519.PublicKey(raw)}, nil
+	case len(raw) == 2+8+ed25519.PublicKeySize && string(raw[:2]) == "Ed":
+		pk := PublicKey{Key: ed25519.PublicKey(raw[10:]), hasID: true}
+		copy(pk.ID[:], raw[2:10])
+		return pk, nil
+	}
+	return PublicKey{}, fmt.Errorf("public key is neither a minisign key nor a 32-byte ed25519 key")
+}
+
+// lastLine returns the last non-empty line of s, skipping the comment line of
+// a minisign file.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// detached is a parsed signature file.
+type detached struct {
+	// algorithm is "Ed" (signs the data), "ED" (signs the BLAKE2b-512 hash
+	// of the data) or empty for a raw signature.
+	algorithm string
+	keyID     [8]byte
+	sig       []byte
+	// minisign signatures also sign a trusted comment.
+	trustedComment string
+	globalSig      []byte
+}
+
+// parseSignature parses a minisign signature file, or a raw ed25519
+// signature in binary or base64.
+func parseSignature(data []byte) (detached, error) {
+	if len(data) == ed25519.SignatureSize {
+		return detached{sig: data}, nil
+	}
+
+	text := strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	if !strings.HasPrefix(text, "untrusted comment:") {
+		sig, err := base64.StdEncoding.DecodeString(text)
+		if err != nil || len(sig) != ed25519.SignatureSize {
+			return detached{}, errors.New("not a minisign or ed25519 signature")
+		}
+		return detached{sig: sig}, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) != 4 {
+		return detached{}, errors.New("malformed minisign signature")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return detached{}, errors.New("malformed minisign signature")
+	}
+	d := detached{algorithm: string(raw[:2]), sig: raw[10:]}
+	if d.algorithm != "Ed" && d.algorithm != "ED" {
+		return detached{}, fmt.Errorf("unsupported signature algorithm %q", d.algorithm)
+	}
+	copy(d.keyID[:], raw[2:10])
+
+	comment, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return detached{}, errors.New("malformed minisign signature: missing trusted comment")
+	}
+	d.trustedComment = comment
+	d.globalSig, err = base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(d.globalSig) != ed25519.SignatureSize {
+		return detached{}, errors.New("malformed minisign signature: bad trusted comment signature")
+	}
+	return d, nil
+}
+
+// Verifier checks signatures against a set of trusted keys.
+type Verifier struct {
+	keys []PublicKey
+}
+
+func NewVerifier(keys []PublicKey) *Verifier {
+	return &Verifier{keys: keys}
+}
+
+// Verify checks that sig is a valid signature of data by a trusted key.
+func (v *Verifier) Verify(data, sig []byte) error {
+	d, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+	if len(v.keys) == 0 {
+		return errors.New("no trusted keys are configured")
+	}
+
+	msg := data
+	if d.algorithm == "ED" {
+		sum := blake2b.Sum512(data)
+		msg = sum[:]
+	}
+
+	for _, k := range v.keys {
+		if d.algorithm != "" && k.hasID && k.ID != d.keyID {
+			continue
+		}
+		if !ed25519.Verify(k.Key, msg, d.sig) {
+			continue
+		}
+		if d.algorithm != "" && !ed25519.Verify(k.Key, append(bytes.Clone(d.sig), d.trustedComment...), d.globalSig) {
+			return errors.New("trusted comment signature does not match")
+		}
+		return nil
+	}
+	return errors.New("signature does not match any trusted key")
+}
+
+// SignaturePath returns the signature file of a list, or "" if it has none.
+func SignaturePath(listPath string) string {
+	for _, ext := range Extensions {
+		if info, err := os.Stat(listPath + ext); err == nil && info.Mode().IsRegular() {
+			return listPath + ext
+		}
+	}
+	return ""
+}
+
+// VerifyFile checks the signature next to a list file. Directories can't be
+// signed and are reported as unsigned. The error explains an invalid
+// signature.
+func (v *Verifier) VerifyFile(listPath string) (Status, error) {
+	status, _, err := v.ReadVerified(listPath)
+	return status, err
+}
+
+// ReadVerified is VerifyFile that also returns the contents of a signed
+// list, as read for the check. Loading the list from these bytes rather than
+// reading the file again ensures that what is loaded is what was verified.
+// The contents are nil for an unsigned list.
+func (v *Verifier) ReadVerified(listPath string) (Status, []byte, error) {
+	sigPath := SignaturePath(listPath)
+	if sigPath == "" {
+		return StatusUnsigned, nil, nil
+	}
+	if info, err := os.Stat(listPath); err != nil || info.IsDir() {
+		return StatusUnsigned, nil, nil
+	}
+
+	data, err := os.ReadFile(listPath)
+	if err != nil {
+		return StatusInvalid, nil, err
+	}
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return StatusInvalid, data, err
+	}
+	if err := v.Verify(data, sig); err != nil {
+		return StatusInvalid, data, fmt.Errorf("%s: %w", sigPath, err)
+	}
+	return StatusVerified, data, nil
+}
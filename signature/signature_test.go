@@ -0,0 +1,201 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignKey returns a key pair with the public key in minisign's .pub
+// format.
+func minisignKey(t *testing.T) (ed25519.PrivateKey, [8]byte, string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id [8]byte
+	copy(id[:], "keyid123")
+	raw := append(append([]byte("Ed"), id[:]...), pub...)
+	return priv, id, "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(raw) + "\n"
+}
+
+// minisign signs data like `minisign -S`, prehashing when alg is "ED".
+func minisign(priv ed25519.PrivateKey, id [8]byte, alg string, data []byte, comment string) []byte {
+	msg := data
+	if alg == "ED" {
+		sum := blake2b.Sum512(data)
+		msg = sum[:]
+	}
+	sig := ed25519.Sign(priv, msg)
+	global := ed25519.Sign(priv, append(append([]byte{}, sig...), comment...))
+	raw := append(append([]byte(alg), id[:]...), sig...)
+	return []byte("untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(raw) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n")
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		in       string
+		haveKeys bool
+		want     Policy
+	}{
+		{"", false, PolicyOff},
+		{"", true, PolicyWarn},
+		{"Require", false, PolicyRequire},
+		{"off", true, PolicyOff},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy(tt.in, tt.haveKeys)
+		if err != nil || got != tt.want {
+			t.Fatalf("ParsePolicy(%q, %v) = %q, %v; want %q", tt.in, tt.haveKeys, got, err, tt.want)
+		}
+	}
+	if _, err := ParsePolicy("strict", true); err == nil {
+		t.Fatal("expected an error for an unknown policy")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	_, id, pub := minisignKey(t)
+	k, err := ParsePublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !k.hasID || k.ID != id {
+		t.Fatalf("key ID = %x, want %x", k.ID, id)
+	}
+
+	// the second line alone is enough
+	if _, err := ParsePublicKey(strings.Split(pub, "\n")[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _, _ := ed25519.GenerateKey(rand.Reader)
+	k, err = ParsePublicKey(base64.StdEncoding.EncodeToString(raw))
+	if err != nil || k.hasID {
+		t.Fatalf("raw key: %+v, %v", k, err)
+	}
+
+	for _, bad := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := ParsePublicKey(bad); err == nil {
+			t.Fatalf("ParsePublicKey(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestVerify_Minisign(t *testing.T) {
+	priv, id, pub := minisignKey(t)
+	key, err := ParsePublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier([]PublicKey{key})
+	data := []byte("pkg:npm/evil-pkg@1.0.0\n")
+
+	for _, alg := range []string{"Ed", "ED"} {
+		if err := v.Verify(data, minisign(priv, id, alg, data, "timestamp:1732752000")); err != nil {
+			t.Fatalf("%s: %v", alg, err)
+		}
+		if err := v.Verify([]byte("tampered\n"), minisign(priv, id, alg, data, "timestamp:1732752000")); err == nil {
+			t.Fatalf("%s: tampered data verified", alg)
+		}
+	}
+
+	// the trusted comment is signed too
+	sig := minisign(priv, id, "ED", data, "timestamp:1732752000")
+	forged := strings.Replace(string(sig), "timestamp:1732752000", "timestamp:1999999999", 1)
+	if err := v.Verify(data, []byte(forged)); err == nil {
+		t.Fatal("forged trusted comment verified")
+	}
+
+	// a signature naming another key ID is not tried against this key
+	var other [8]byte
+	copy(other[:], "otherkey")
+	if err := v.Verify(data, minisign(priv, other, "Ed", data, "c")); err == nil {
+		t.Fatal("signature with another key ID verified")
+	}
+}
+
+func TestVerify_RawSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	v := NewVerifier([]PublicKey{{Key: pub}})
+	data := []byte("evil-pkg@1.0.0\n")
+	sig := ed25519.Sign(priv, data)
+
+	if err := v.Verify(data, sig); err != nil {
+		t.Fatalf("binary: %v", err)
+	}
+	if err := v.Verify(data, []byte(base64.StdEncoding.EncodeToString(sig)+"\n")); err != nil {
+		t.Fatalf("base64: %v", err)
+	}
+
+	_, untrusted, _ := ed25519.GenerateKey(rand.Reader)
+	if err := v.Verify(data, ed25519.Sign(untrusted, data)); err == nil {
+		t.Fatal("signature by an untrusted key verified")
+	}
+	if err := NewVerifier(nil).Verify(data, sig); err == nil {
+		t.Fatal("verified without trusted keys")
+	}
+	if err := v.Verify(data, []byte("garbage")); err == nil {
+		t.Fatal("garbage signature verified")
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	priv, id, pub := minisignKey(t)
+	key, _ := ParsePublicKey(pub)
+	v := NewVerifier([]PublicKey{key})
+
+	dir := t.TempDir()
+	data := []byte("evil-pkg@1.0.0\n")
+	signed := filepath.Join(dir, "signed.txt")
+	unsigned := filepath.Join(dir, "unsigned.txt")
+	invalid := filepath.Join(dir, "invalid.txt")
+	for _, p := range []string{signed, unsigned, invalid} {
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(signed+".minisig", minisign(priv, id, "ED", data, "c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid+".sig", minisign(priv, id, "ED", []byte("other\n"), "c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want Status
+	}{
+		{signed, StatusVerified},
+		{unsigned, StatusUnsigned},
+		{invalid, StatusInvalid},
+		{dir, StatusUnsigned},
+	}
+	for _, tt := range tests {
+		got, err := v.VerifyFile(tt.path)
+		if got != tt.want {
+			t.Fatalf("VerifyFile(%s) = %s (%v), want %s", filepath.Base(tt.path), got, err, tt.want)
+		}
+		if (err != nil) != (tt.want == StatusInvalid) {
+			t.Fatalf("VerifyFile(%s) error = %v", filepath.Base(tt.path), err)
+		}
+	}
+}
+
+func TestIsSignatureFile(t *testing.T) {
+	for name, want := range map[string]bool{"npm.txt.sig": true, "npm.txt.MINISIG": true, "npm.txt": false, "signatures.json": false} {
+		if got := IsSignatureFile(name); got != want {
+			t.Fatalf("IsSignatureFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
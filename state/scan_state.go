@@ -6,10 +6,117 @@ import (
 	"path/filepath"
 )
 
-// LoadScanState reads the scan-state file (JSON map[string]int64).
+// FormatVersion is the version of the records written by this build. It is
+// bumped whenever readers or matching change what a scan would find, so
+// records from older builds are rescanned rather than trusted.
+const FormatVersion = 2
+
+// ScanState maps absolute, cleaned dependency file paths to what was learned
+// the last time each file was scanned.
+type ScanState map[string]FileState
+
+// FileState is the persisted record for a single dependency file.
+type FileState struct {
+	// ScannedAt is the time of the last successful scan in UnixNano.
+	ScannedAt int64 `json:"scanned_at"`
+	// Reader is the name of the reader that parsed the file.
+	Reader string `json:"reader,omitempty"`
+	// Findings are the bad packages found in the file during that scan. They
+	// are replayed when the file is skipped so incremental runs still report
+	// infections that are on disk.
+	Findings []Finding `json:"findings,omitempty"`
+	// Version is the FormatVersion of the build that wrote the record.
+	Version int `json:"version,omitempty"`
+	// Lists is the fingerprint of the bad package lists and list settings
+	// the file was checked against.
+	Lists string `json:"lists,omitempty"`
+	// Sources are the other files the reader consulted, such as included
+	// requirements files, as they were when the file was scanned.
+	Sources []Source `json:"sources,omitempty"`
+}
+
+// Source is a file a scan result depends on besides the scanned file.
+type Source struct {
+	Path string `json:"path"`
+	// ModTime is the modification time in UnixNano, or 0 if the file did
+	// not exist.
+	ModTime int64 `json:"mod_time,omitempty"`
+}
+
+// NewSources records the current modification times of paths.
+func NewSources(paths []string) []Source {
+	var sources []Source
+	for _, p := range paths {
+		sources = append(sources, Source{Path: p, ModTime: modTime(p)})
+	}
+	return sources
+}
+
+// SourcesChanged reports whether any source was created, removed or
+// modified since the record was made.
+func (fs FileState) SourcesChanged() bool {
+	for _, s := range fs.Sources {
+		if modTime(s.Path) != s.ModTime {
+			return true
+		}
+	}
+	return false
+}
+
+func modTime(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
+
+// Current reports whether the record was written by this format version
+// against the lists with the given fingerprint. Legacy records, which hold
+// only a timestamp, are never current: their findings were not recorded.
+func (fs FileState) Current(listsFingerprint string) bool {
+	return fs.Reader != "" && fs.Version == FormatVersion && fs.Lists == listsFingerprint
+}
+
+// Finding is a persisted match of a dependency against a bad package list.
+type Finding struct {
+	Package    string   `json:"package"`
+	Version    string   `json:"version"`
+	Ecosystem  string   `json:"ecosystem,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	Line       int      `json:"line,omitempty"`
+	List       string   `json:"list"`
+	ListPath   string   `json:"list_path,omitempty"`
+	Advisory   string   `json:"advisory,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Severity   string   `json:"severity,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+	References []string `json:"references,omitempty"`
+	Added      string   `json:"added,omitempty"`
+}
+
+// UnmarshalJSON accepts both the current object form and the legacy form
+// where each path mapped directly to a UnixNano timestamp.
+func (fs *FileState) UnmarshalJSON(data []byte) error {
+	var ts int64
+	if err := json.Unmarshal(data, &ts); err == nil {
+		*fs = FileState{ScannedAt: ts}
+		return nil
+	}
+
+	type plain FileState
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*fs = FileState(p)
+	return nil
+}
+
+// LoadScanState reads the scan-state file (JSON object keyed by path).
 // It normalizes the keys to absolute cleaned paths before returning.
-func LoadScanState(path string) map[string]int64 {
-	state := make(map[string]int64)
+func LoadScanState(path string) ScanState {
+	state := make(ScanState)
 	if path == "" {
 		return state
 	}
@@ -20,11 +127,11 @@ func LoadScanState(path string) map[string]int64 {
 	}
 
 	if err := json.Unmarshal(data, &state); err != nil {
-		return make(map[string]int64)
+		return make(ScanState)
 	}
 
 	// normalize keys to absolute cleaned paths so state is robust
-	normalized := make(map[string]int64, len(state))
+	normalized := make(ScanState, len(state))
 	for k, v := range state {
 		nk := k
 		if !filepath.IsAbs(nk) {
@@ -40,7 +147,7 @@ func LoadScanState(path string) map[string]int64 {
 }
 
 // SaveScanState writes the scan state to disk atomically (tmp then rename).
-func SaveScanState(path string, state map[string]int64) error {
+func SaveScanState(path string, state ScanState) error {
 	if path == "" {
 		return nil
 	}
@@ -3,6 +3,7 @@ package state
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -12,7 +13,7 @@ func TestSaveLoadScanState_Normalization(t *testing.T) {
 	path := filepath.Join(tmpDir, "scan_state.json")
 
 	// create a state with a relative key
-	state := map[string]int64{"./some/file": time.Now().Add(-time.Hour).UnixNano()}
+	state := ScanState{"./some/file": {ScannedAt: time.Now().Add(-time.Hour).UnixNano()}}
 
 	if err := SaveScanState(path, state); err != nil {
 		t.Fatalf("SaveScanState: %v", err)
@@ -33,3 +34,101 @@ func TestSaveLoadScanState_Normalization(t *testing.T) {
 	// cleanup
 	os.Remove(path)
 }
+
+func TestSaveLoadScanState_Findings(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "scan_state.json")
+	file := filepath.Join(tmpDir, "package-lock.json")
+
+	state := ScanState{file: {
+		ScannedAt: time.Now().UnixNano(),
+		Reader:    "package-lock.json",
+		Findings:  []Finding{{Package: "left-pad", Version: "1.2.3", List: "npm.yaml", Severity: "high", References: []string{"https://example.com/left-pad"}}},
+	}}
+
+	if err := SaveScanState(path, state); err != nil {
+		t.Fatalf("SaveScanState: %v", err)
+	}
+
+	loaded := LoadScanState(path)
+	got := loaded[file]
+	if got.Reader != "package-lock.json" {
+		t.Fatalf("expected reader to round-trip, got %q", got.Reader)
+	}
+	if len(got.Findings) != 1 || !reflect.DeepEqual(got.Findings[0], state[file].Findings[0]) {
+		t.Fatalf("expected findings to round-trip, got %+v", got.Findings)
+	}
+}
+
+func TestLoadScanState_LegacyTimestamps(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "scan_state.json")
+	file := filepath.Join(tmpDir, "package-lock.json")
+
+	legacy := `{"` + file + `": 1700000000000000000}`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("write legacy state: %v", err)
+	}
+
+	loaded := LoadScanState(path)
+	if got := loaded[file].ScannedAt; got != 1700000000000000000 {
+		t.Fatalf("expected legacy timestamp to load, got %d", got)
+	}
+	if len(loaded[file].Findings) != 0 {
+		t.Fatalf("expected no findings for legacy entry")
+	}
+	// the legacy entry recorded no findings, so it must be rescanned
+	if loaded[file].Current("") {
+		t.Fatalf("expected legacy entry to require a rescan")
+	}
+}
+
+func TestFileState_Current(t *testing.T) {
+	fs := FileState{ScannedAt: 1, Reader: "package-lock.json", Version: FormatVersion, Lists: "abc"}
+	if !fs.Current("abc") {
+		t.Fatalf("expected record to be current")
+	}
+	if fs.Current("def") {
+		t.Fatalf("expected record checked against other lists to be stale")
+	}
+	old := fs
+	old.Version = FormatVersion - 1
+	if old.Current("abc") {
+		t.Fatalf("expected record from an older format to be stale")
+	}
+	noReader := fs
+	noReader.Reader = ""
+	if noReader.Current("abc") {
+		t.Fatalf("expected record without a reader to be stale")
+	}
+}
+
+func TestFileState_SourcesChanged(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.txt")
+	if err := os.WriteFile(base, []byte("requests==2.31.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+
+	fs := FileState{Sources: NewSources([]string{base, missing})}
+	if fs.SourcesChanged() {
+		t.Fatalf("expected unchanged sources")
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(base, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !fs.SourcesChanged() {
+		t.Fatalf("expected a modified source to be a change")
+	}
+
+	fs = FileState{Sources: NewSources([]string{base, missing})}
+	if err := os.WriteFile(missing, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !fs.SourcesChanged() {
+		t.Fatalf("expected a created source to be a change")
+	}
+}
@@ -0,0 +1,194 @@
+package versions
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// mavenQualifiers ranks the well-known qualifiers the way Maven's
+// ComparableVersion does. Unknown qualifiers sort after all of them.
+var mavenQualifiers = map[string]int{
+	"alpha":     0,
+	"beta":      1,
+	"milestone": 2,
+	"rc":        3,
+	"cr":        3,
+	"snapshot":  4,
+	"":          5,
+	"ga":        5,
+	"final":     5,
+	"release":   5,
+	"sp":        6,
+}
+
+// mavenItems splits a version into numeric and qualifier items at dots,
+// hyphens and transitions between digits and letters: 1.0-RC1 becomes
+// 1, 0, rc, 1.
+func mavenItems(version string) []string {
+	var items []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			items = append(items, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(version) {
+		switch {
+		case r == '.' || r == '-' || r == '_':
+			flush()
+			continue
+		case cur.Len() > 0 && isDigit(rune(cur.String()[0])) != isDigit(r):
+			flush()
+		}
+		cur.WriteRune(r)
+	}
+	flush()
+
+	// a, b and m directly followed by a number are shorthands
+	for i, item := range items {
+		if i+1 < len(items) && isDigit(rune(items[i+1][0])) {
+			switch item {
+			case "a":
+				items[i] = "alpha"
+			case "b":
+				items[i] = "beta"
+			case "m":
+				items[i] = "milestone"
+			}
+		}
+	}
+	return items
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+// compareMaven compares two Maven (or NuGet) versions. Missing trailing items
+// compare as 0 or as a release, so 1.0 equals 1.0.0 and 1.0-SNAPSHOT sorts
+// before 1.0.
+func compareMaven(a, b string) int {
+	ai, bi := mavenItems(a), mavenItems(b)
+	for i := 0; i < len(ai) || i < len(bi); i++ {
+		var x, y string
+		if i < len(ai) {
+			x = ai[i]
+		}
+		if i < len(bi) {
+			y = bi[i]
+		}
+		if c := compareMavenItems(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareMavenItems compares two items; "" is a missing item.
+func compareMavenItems(x, y string) int {
+	xNum := x != "" && isDigit(rune(x[0]))
+	yNum := y != "" && isDigit(rune(y[0]))
+	switch {
+	case xNum && yNum:
+		return compareNumeric(x, y)
+	case xNum:
+		if y == "" {
+			return compareNumeric(x, "0")
+		}
+		return 1 // numbers sort after qualifiers
+	case yNum:
+		if x == "" {
+			return compareNumeric("0", y)
+		}
+		return -1
+	}
+
+	xr, xKnown := mavenQualifiers[x]
+	yr, yKnown := mavenQualifiers[y]
+	switch {
+	case xKnown && yKnown:
+		return cmp.Compare(xr, yr)
+	case xKnown:
+		return -1
+	case yKnown:
+		return 1
+	}
+	return strings.Compare(x, y)
+}
+
+// compareNumeric compares two strings of digits of any length.
+func compareNumeric(x, y string) int {
+	x = strings.TrimLeft(x, "0")
+	y = strings.TrimLeft(y, "0")
+	if c := cmp.Compare(len(x), len(y)); c != 0 {
+		return c
+	}
+	return strings.Compare(x, y)
+}
+
+// mavenInterval is one interval of a range. Empty bounds are unbounded.
+type mavenInterval struct {
+	lower, upper       string
+	lowerInc, upperInc bool
+}
+
+func (iv mavenInterval) contains(version string) bool {
+	if iv.lower != "" {
+		c := compareMaven(version, iv.lower)
+		if c < 0 || c == 0 && !iv.lowerInc {
+			return false
+		}
+	}
+	if iv.upper != "" {
+		c := compareMaven(version, iv.upper)
+		if c > 0 || c == 0 && !iv.upperInc {
+			return false
+		}
+	}
+	return true
+}
+
+// mavenRange is a union of intervals such as [1.0,1.2),[1.5,).
+type mavenRange []mavenInterval
+
+func (r mavenRange) Matches(version string) bool {
+	for _, iv := range r {
+		if iv.contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMavenRange(spec string) (Constraint, error) {
+	var r mavenRange
+	rest := strings.TrimSpace(spec)
+	for rest != "" {
+		if rest[0] != '[' && rest[0] != '(' {
+			return nil, fmt.Errorf("invalid range %q", spec)
+		}
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid range %q: unclosed interval", spec)
+		}
+
+		iv := mavenInterval{lowerInc: rest[0] == '[', upperInc: rest[end] == ']'}
+		inner := rest[1:end]
+		if lower, upper, ok := strings.Cut(inner, ","); ok {
+			iv.lower, iv.upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+		} else {
+			// [1.0] is exactly 1.0
+			if !iv.lowerInc || !iv.upperInc || strings.TrimSpace(inner) == "" {
+				return nil, fmt.Errorf("invalid range %q", spec)
+			}
+			iv.lower = strings.TrimSpace(inner)
+			iv.upper = iv.lower
+		}
+		r = append(r, iv)
+
+		rest = strings.TrimSpace(rest[end+1:])
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, ","))
+	}
+	return r, nil
+}
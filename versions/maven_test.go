@@ -0,0 +1,46 @@
+package versions
+
+import "testing"
+
+func TestCompareMaven(t *testing.T) {
+	ordered := []string{"1.0-alpha-1", "1.0-beta", "1.0-M2", "1.0-RC1", "1.0-SNAPSHOT", "1.0", "1.0-sp1", "1.0.1", "1.10"}
+	for i := 1; i < len(ordered); i++ {
+		if compareMaven(ordered[i-1], ordered[i]) >= 0 {
+			t.Fatalf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+	for _, pair := range [][2]string{{"1.0", "1.0.0"}, {"1.0.0.RELEASE", "1.0"}, {"1.0-ga", "1.0-final"}} {
+		if compareMaven(pair[0], pair[1]) != 0 {
+			t.Fatalf("expected %s == %s", pair[0], pair[1])
+		}
+	}
+}
+
+func TestMavenRange(t *testing.T) {
+	tests := []struct {
+		spec    string
+		matches []string
+		misses  []string
+	}{
+		{"[1.0,2.0)", []string{"1.0", "1.9.9"}, []string{"2.0", "0.9"}},
+		{"(,1.0]", []string{"0.1", "1.0"}, []string{"1.0.1"}},
+		{"[1.5]", []string{"1.5", "1.5.0"}, []string{"1.5.1"}},
+		{"[1.0,1.2),[1.5,)", []string{"1.1", "7.0"}, []string{"1.3"}},
+	}
+	for _, tt := range tests {
+		c, err := parseMavenRange(tt.spec)
+		if err != nil {
+			t.Fatalf("parseMavenRange(%q): %v", tt.spec, err)
+		}
+		for _, v := range tt.matches {
+			if !c.Matches(v) {
+				t.Fatalf("expected %q to match %s", tt.spec, v)
+			}
+		}
+		for _, v := range tt.misses {
+			if c.Matches(v) {
+				t.Fatalf("expected %q not to match %s", tt.spec, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package versions
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440Pattern is the version pattern from PEP 440 appendix B.
+var pep440Pattern = regexp.MustCompile(`(?i)^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+	`(?:\+[a-z0-9]+(?:[-_.][a-z0-9]+)*)?$`)
+
+// pep440Version is a parsed Python version. Absent pre, post and dev
+// segments hold sentinel values so versions compare field by field.
+type pep440Version struct {
+	epoch   int
+	release []int
+	pre     [2]int // phase (0 a, 1 b, 2 rc) and number
+	post    int
+	dev     int
+}
+
+func parsePEP440(s string) (pep440Version, error) {
+	m := pep440Pattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return pep440Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	var v pep440Version
+	v.epoch = atoi(m[1])
+	for _, f := range strings.Split(m[2], ".") {
+		v.release = append(v.release, atoi(f))
+	}
+
+	hasPost := m[5] != "" || m[6] != ""
+	hasDev := m[8] != ""
+	switch strings.ToLower(m[3]) {
+	case "":
+		v.pre = [2]int{math.MaxInt, 0}
+		if hasDev && !hasPost {
+			// 1.0.dev1 sorts before 1.0a1
+			v.pre = [2]int{-1, 0}
+		}
+	case "a", "alpha":
+		v.pre = [2]int{0, atoi(m[4])}
+	case "b", "beta":
+		v.pre = [2]int{1, atoi(m[4])}
+	default:
+		v.pre = [2]int{2, atoi(m[4])}
+	}
+
+	v.post = -1
+	if hasPost {
+		v.post = atoi(m[5] + m[7])
+	}
+
+	v.dev = math.MaxInt
+	if hasDev {
+		v.dev = atoi(m[9])
+	}
+	return v, nil
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func comparePEP440(a, b pep440Version) int {
+	if c := cmp.Compare(a.epoch, b.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(a.release) || i < len(b.release); i++ {
+		if c := cmp.Compare(releasePart(a.release, i), releasePart(b.release, i)); c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(a.pre[0], b.pre[0]); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.pre[1], b.pre[1]); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.post, b.post); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.dev, b.dev)
+}
+
+func releasePart(release []int, i int) int {
+	if i < len(release) {
+		return release[i]
+	}
+	return 0
+}
+
+type pep440Specifier struct {
+	op      string
+	version string // as written, for === and prefix matches
+	v       pep440Version
+}
+
+func (s pep440Specifier) matches(version string, v pep440Version) bool {
+	switch s.op {
+	case "===":
+		return strings.EqualFold(version, s.version)
+	case "==", "!=":
+		eq := comparePEP440(v, s.v) == 0
+		if prefix, ok := strings.CutSuffix(s.version, ".*"); ok {
+			eq = matchesReleasePrefix(v, prefix)
+		}
+		return eq == (s.op == "==")
+	case "~=":
+		// ~=1.4.5 is >=1.4.5, ==1.4.*
+		prefix := s.v
+		prefix.release = prefix.release[:len(prefix.release)-1]
+		return comparePEP440(v, s.v) >= 0 && releaseHasPrefix(v, prefix)
+	}
+
+	c := comparePEP440(v, s.v)
+	switch s.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+// matchesReleasePrefix reports whether v matches a ==prefix.* specifier.
+// Only the release segment is compared, so ==1.0.* matches 1.0.post1 and
+// 1.0rc1 but not 1.1.
+func matchesReleasePrefix(v pep440Version, prefix string) bool {
+	p, err := parsePEP440(prefix)
+	if err != nil {
+		return false
+	}
+	return releaseHasPrefix(v, p)
+}
+
+func releaseHasPrefix(v, prefix pep440Version) bool {
+	if v.epoch != prefix.epoch {
+		return false
+	}
+	for i, n := range prefix.release {
+		if releasePart(v.release, i) != n {
+			return false
+		}
+	}
+	return true
+}
+
+// pep440Specifiers is a comma-separated list of PEP 440 version specifiers,
+// all of which must match.
+type pep440Specifiers []pep440Specifier
+
+func (ss pep440Specifiers) Matches(version string) bool {
+	v, err := parsePEP440(version)
+	if err != nil {
+		return false
+	}
+	for _, s := range ss {
+		if !s.matches(version, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func parsePEP440Specifiers(spec string) (Constraint, error) {
+	var ss pep440Specifiers
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		op := ""
+		for _, o := range []string{"===", "~=", "==", "!=", "<=", ">=", "<", ">"} {
+			if strings.HasPrefix(part, o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("invalid specifier %q in %q", part, spec)
+		}
+
+		s := pep440Specifier{op: op, version: strings.TrimSpace(part[len(op):])}
+		if op == "===" {
+			ss = append(ss, s)
+			continue
+		}
+
+		parseable := s.version
+		if op == "==" || op == "!=" {
+			parseable = strings.TrimSuffix(parseable, ".*")
+		}
+		v, err := parsePEP440(parseable)
+		if err != nil {
+			return nil, fmt.Errorf("invalid specifier %q in %q: %w", part, spec, err)
+		}
+		if op == "~=" && len(v.release) < 2 {
+			return nil, fmt.Errorf("invalid specifier %q in %q: ~= needs at least two release components", part, spec)
+		}
+		s.v = v
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
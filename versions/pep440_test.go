@@ -0,0 +1,51 @@
+package versions
+
+import "testing"
+
+func TestComparePEP440(t *testing.T) {
+	ordered := []string{"1.0.dev0", "1.0a1", "1.0b2.dev1", "1.0b2", "1.0rc1", "1.0", "1.0.post1.dev0", "1.0.post1", "1.1", "1!0.1"}
+	for i := 1; i < len(ordered); i++ {
+		a, err := parsePEP440(ordered[i-1])
+		if err != nil {
+			t.Fatalf("parsePEP440(%q): %v", ordered[i-1], err)
+		}
+		b, err := parsePEP440(ordered[i])
+		if err != nil {
+			t.Fatalf("parsePEP440(%q): %v", ordered[i], err)
+		}
+		if comparePEP440(a, b) >= 0 {
+			t.Fatalf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestPEP440Specifiers(t *testing.T) {
+	tests := []struct {
+		spec    string
+		matches []string
+		misses  []string
+	}{
+		{">=1.0,<1.2", []string{"1.0", "1.1.9"}, []string{"1.2", "0.9"}},
+		{"==1.0.*", []string{"1.0", "1.0.5", "1.0rc1"}, []string{"1.1", "1.10"}},
+		{"!=1.1", []string{"1.0", "1.1.1"}, []string{"1.1", "1.1.0"}},
+		{"~=1.4.5", []string{"1.4.5", "1.4.9"}, []string{"1.5.0", "1.4.4"}},
+		{"~=2.2", []string{"2.2", "2.9"}, []string{"3.0"}},
+		{"===1.0+local", []string{"1.0+local"}, []string{"1.0"}},
+	}
+	for _, tt := range tests {
+		c, err := parsePEP440Specifiers(tt.spec)
+		if err != nil {
+			t.Fatalf("parsePEP440Specifiers(%q): %v", tt.spec, err)
+		}
+		for _, v := range tt.matches {
+			if !c.Matches(v) {
+				t.Fatalf("expected %q to match %s", tt.spec, v)
+			}
+		}
+		for _, v := range tt.misses {
+			if c.Matches(v) {
+				t.Fatalf("expected %q not to match %s", tt.spec, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package versions
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version. Build metadata is ignored, as it is
+// for precedence.
+type semver struct {
+	major, minor, patch int
+	pre                 []string
+}
+
+// parseSemver parses a complete version such as 1.2.3, v1.2.3 (Go) or
+// 1.2.3-beta.1+build.
+func parseSemver(s string) (semver, error) {
+	v, parts, err := parsePartialSemver(s)
+	if err != nil {
+		return semver{}, err
+	}
+	if parts < 3 {
+		return semver{}, fmt.Errorf("incomplete version %q", s)
+	}
+	return v, nil
+}
+
+// parsePartialSemver parses a version that may leave out or wildcard trailing
+// components (1, 1.2, 1.2.x, *). It returns the number of components given.
+func parsePartialSemver(s string) (semver, int, error) {
+	rest := strings.TrimPrefix(s, "v")
+	rest, _, _ = strings.Cut(rest, "+")
+	core, pre, hasPre := strings.Cut(rest, "-")
+
+	var v semver
+	fields := strings.Split(core, ".")
+	if len(fields) > 3 {
+		return semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.major, &v.minor, &v.patch}
+	parts := 0
+	for i, f := range fields {
+		if f == "x" || f == "X" || f == "*" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || parts != i {
+			return semver{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+		parts++
+	}
+
+	if hasPre {
+		if parts < 3 || pre == "" {
+			return semver{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		v.pre = strings.Split(pre, ".")
+	}
+	return v, parts, nil
+}
+
+func compareSemver(a, b semver) int {
+	if c := cmp.Compare(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.minor, b.minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.patch, b.patch); c != 0 {
+		return c
+	}
+
+	// a version without pre-release identifiers has higher precedence
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		an, aErr := strconv.Atoi(a.pre[i])
+		bn, bErr := strconv.Atoi(b.pre[i])
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = cmp.Compare(an, bn)
+		case aErr == nil:
+			c = -1 // numeric identifiers sort before alphanumeric ones
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(a.pre[i], b.pre[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a.pre), len(b.pre))
+}
+
+// bumpSemver returns the lowest version above every version matching the
+// first parts components of v, e.g. 1.3.0-0 for 1.2.x.
+func bumpSemver(v semver, parts int) semver {
+	switch parts {
+	case 1:
+		return semver{major: v.major + 1, pre: []string{"0"}}
+	case 2:
+		return semver{major: v.major, minor: v.minor + 1, pre: []string{"0"}}
+	}
+	return semver{major: v.major, minor: v.minor, patch: v.patch + 1, pre: []string{"0"}}
+}
+
+type semverComparator struct {
+	op string
+	v  semver
+}
+
+func (c semverComparator) matches(v semver) bool {
+	n := compareSemver(v, c.v)
+	switch c.op {
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	}
+	return n == 0
+}
+
+// semverRange is a set of alternatives (joined by ||), each of which is a set
+// of comparators that must all match.
+//
+// Unlike npm, pre-releases inside a range match even when no comparator names
+// a pre-release of the same version: for a bad list, 1.2.0-beta.1 is as
+// suspicious as 1.2.0.
+type semverRange [][]semverComparator
+
+func (r semverRange) Matches(version string) bool {
+	v, err := parseSemver(version)
+	if err != nil {
+		return false
+	}
+	for _, set := range r {
+		ok := true
+		for _, c := range set {
+			if !c.matches(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSemverRange(spec string) (Constraint, error) {
+	var r semverRange
+	for _, alt := range strings.Split(spec, "||") {
+		set, err := parseSemverComparators(alt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q: %w", spec, err)
+		}
+		r = append(r, set)
+	}
+	return r, nil
+}
+
+// parseSemverComparators parses a space (or, as Cargo writes it, comma)
+// separated list of comparators, or a hyphen range.
+func parseSemverComparators(s string) ([]semverComparator, error) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	if len(fields) == 0 {
+		return nil, errors.New("empty range")
+	}
+
+	// join operators written apart from their version (">= 1.0.0")
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		tok := fields[i]
+		if strings.Trim(tok, "<>=^~") == "" && i+1 < len(fields) {
+			tok += fields[i+1]
+			i++
+		}
+		tokens = append(tokens, tok)
+	}
+
+	if len(tokens) == 3 && tokens[1] == "-" {
+		return parseHyphenRange(tokens[0], tokens[2])
+	}
+
+	var set []semverComparator
+	for _, tok := range tokens {
+		cs, err := parseSemverComparator(tok)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, cs...)
+	}
+	return set, nil
+}
+
+func parseHyphenRange(from, to string) ([]semverComparator, error) {
+	lo, _, err := parsePartialSemver(from)
+	if err != nil {
+		return nil, err
+	}
+	hi, parts, err := parsePartialSemver(to)
+	if err != nil {
+		return nil, err
+	}
+
+	set := []semverComparator{{">=", lo}}
+	switch parts {
+	case 0:
+	case 3:
+		set = append(set, semverComparator{"<=", hi})
+	default:
+		set = append(set, semverComparator{"<", bumpSemver(hi, parts)})
+	}
+	return set, nil
+}
+
+// parseSemverComparator expands one comparator, which may use a partial
+// version or the ^ and ~ shorthands, into primitive comparators.
+func parseSemverComparator(tok string) ([]semverComparator, error) {
+	op := ""
+	for _, o := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(tok, o) {
+			op = o
+			break
+		}
+	}
+	v, parts, err := parsePartialSemver(tok[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	if parts == 0 {
+		if op == "<" || op == ">" {
+			return nil, fmt.Errorf("%q matches no version", tok)
+		}
+		return nil, nil // any version
+	}
+
+	switch op {
+	case "", "=":
+		if parts == 3 {
+			return []semverComparator{{"=", v}}, nil
+		}
+		return []semverComparator{{">=", v}, {"<", bumpSemver(v, parts)}}, nil
+	case "^":
+		// allow changes that don't modify the left-most non-zero component
+		upper := bumpSemver(v, 3)
+		switch {
+		case v.major > 0 || parts == 1:
+			upper = bumpSemver(v, 1)
+		case v.minor > 0 || parts == 2:
+			upper = bumpSemver(v, 2)
+		}
+		return []semverComparator{{">=", v}, {"<", upper}}, nil
+	case "~":
+		upper := bumpSemver(v, 2)
+		if parts == 1 {
+			upper = bumpSemver(v, 1)
+		}
+		return []semverComparator{{">=", v}, {"<", upper}}, nil
+	case ">":
+		if parts < 3 {
+			return []semverComparator{{">=", bumpSemver(v, parts)}}, nil
+		}
+	case "<":
+		if parts < 3 {
+			v.pre = []string{"0"}
+		}
+	case "<=":
+		if parts < 3 {
+			return []semverComparator{{"<", bumpSemver(v, parts)}}, nil
+		}
+	}
+	return []semverComparator{{op, v}}, nil
+}
@@ -0,0 +1,54 @@
+package versions
+
+import "testing"
+
+func TestSemverRange(t *testing.T) {
+	tests := []struct {
+		spec    string
+		matches []string
+		misses  []string
+	}{
+		{"^1.2.3", []string{"1.2.3", "1.9.0", "1.3.0-beta.1"}, []string{"1.2.2", "2.0.0", "2.0.0-0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^0.x", []string{"0.0.1", "0.9.9"}, []string{"1.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0"}},
+		{">=4.1.0 <4.1.3", []string{"4.1.0", "4.1.2"}, []string{"4.1.3", "4.0.9"}},
+		{">= 4.1.0, < 4.1.3", []string{"4.1.1"}, []string{"4.1.3"}},
+		{"1.2.x", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{"1.0.0 - 1.2", []string{"1.0.0", "1.2.9"}, []string{"1.3.0"}},
+		{"1.0.0 - 1.2.0", []string{"1.2.0"}, []string{"1.2.1"}},
+		{"<1.2", []string{"1.1.9"}, []string{"1.2.0-beta", "1.2.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"1.0.1 || ^2.1.0 || =3.0.0", []string{"1.0.1", "2.5.0", "3.0.0"}, []string{"1.0.2", "2.0.0", "3.0.1"}},
+	}
+	for _, tt := range tests {
+		c, err := parseSemverRange(tt.spec)
+		if err != nil {
+			t.Fatalf("parseSemverRange(%q): %v", tt.spec, err)
+		}
+		for _, v := range tt.matches {
+			if !c.Matches(v) {
+				t.Fatalf("expected %q to match %s", tt.spec, v)
+			}
+		}
+		for _, v := range tt.misses {
+			if c.Matches(v) {
+				t.Fatalf("expected %q not to match %s", tt.spec, v)
+			}
+		}
+	}
+}
+
+func TestCompareSemver_Prerelease(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := parseSemver(ordered[i-1])
+		b, _ := parseSemver(ordered[i])
+		if compareSemver(a, b) >= 0 {
+			t.Fatalf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+}
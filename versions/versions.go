@@ -0,0 +1,185 @@
+// Package versions compares package versions and evaluates version ranges
+// using the rules of each ecosystem.
+package versions
+
+import (
+	"strings"
+
+	"github.com/joelcma/dewormer/readers"
+)
+
+// Constraint is a set of versions, parsed from a version or range as written
+// in a bad package list.
+type Constraint interface {
+	Matches(version string) bool
+}
+
+// Any matches every version.
+type Any struct{}
+
+func (Any) Matches(string) bool { return true }
+
+// None matches no version.
+type None struct{}
+
+func (None) Matches(string) bool { return false }
+
+// Exact matches a single version, compared as written.
+type Exact string
+
+func (e Exact) Matches(version string) bool { return string(e) == version }
+
+// Parse parses spec, a version or range in the syntax of the given ecosystem:
+//
+//   - "*" matches every version in every ecosystem
+//   - npm, Cargo, Go and Composer use semver ranges (^1.2.0, ~1.2.0,
+//     >=1.0.0 <1.2.0, 1.x, 1.0.0 - 1.2.0, with alternatives joined by ||)
+//   - Maven and NuGet use interval notation ([1.0,2.0), (,1.0], [1.5])
+//   - PyPI uses PEP 440 specifiers (>=1.0,<1.2, ==1.*, ~=1.4, !=1.1)
+//
+// Anything that isn't a range is a single version, which matches versions
+// equal to it in the ecosystem's ordering (see Equal). Other ecosystems, and
+// legacy entries evaluated without an ecosystem, only support versions
+// compared as written (see Exact) and "*".
+func Parse(ecosystem, spec string) (Constraint, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "*" {
+		return Any{}, nil
+	}
+
+	switch ecosystem {
+	case readers.EcosystemNpm, readers.EcosystemCargo, readers.EcosystemGo, readers.EcosystemComposer:
+		if _, err := parseSemver(spec); err == nil {
+			return Equal{Ecosystem: ecosystem, Version: spec}, nil
+		}
+		r, err := parseSemverRange(spec)
+		if err != nil && !strings.ContainsAny(spec, "<>=^~|* ") {
+			// not semver and not meant as a range, e.g. Composer's dev-main
+			return Equal{Ecosystem: ecosystem, Version: spec}, nil
+		}
+		return r, err
+	case readers.EcosystemMaven, readers.EcosystemNuGet:
+		if strings.HasPrefix(spec, "[") || strings.HasPrefix(spec, "(") {
+			return parseMavenRange(spec)
+		}
+		return Equal{Ecosystem: ecosystem, Version: spec}, nil
+	case readers.EcosystemPyPI:
+		if spec != "" && strings.ContainsAny(spec[:1], "=<>!~") {
+			return parsePEP440Specifiers(spec)
+		}
+		return Equal{Ecosystem: ecosystem, Version: spec}, nil
+	}
+	return Exact(spec), nil
+}
+
+// Equal matches versions equal to Version in the ordering of Ecosystem, so
+// the Go versions 1.2.3 and v1.2.3 or the PyPI versions 1.0 and 1.0.0 are
+// equal. Versions that can't be compared must be identical.
+type Equal struct {
+	Ecosystem string
+	Version   string
+}
+
+func (e Equal) Matches(version string) bool {
+	if version == e.Version {
+		return true
+	}
+	c, ok := Compare(e.Ecosystem, version, e.Version)
+	return ok && c == 0
+}
+
+// Interval matches versions from Lower (inclusive) up to Upper (exclusive
+// unless UpperInclusive) in the ordering of Ecosystem. An empty bound is
+// unbounded.
+type Interval struct {
+	Ecosystem      string
+	Lower, Upper   string
+	UpperInclusive bool
+}
+
+func (iv Interval) Matches(version string) bool {
+	if iv.Lower != "" {
+		c, ok := Compare(iv.Ecosystem, version, iv.Lower)
+		if !ok || c < 0 {
+			return false
+		}
+	}
+	if iv.Upper != "" {
+		c, ok := Compare(iv.Ecosystem, version, iv.Upper)
+		if !ok || c > 0 || c == 0 && !iv.UpperInclusive {
+			return false
+		}
+	}
+	return true
+}
+
+// Union matches versions matched by any of its constraints.
+type Union []Constraint
+
+func (u Union) Matches(version string) bool {
+	for _, c := range u {
+		if c.Matches(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare compares two versions using the ordering of ecosystem and returns
+// -1, 0 or +1. It reports false when either version can't be parsed or the
+// ecosystem has no known ordering.
+func Compare(ecosystem, a, b string) (int, bool) {
+	switch ecosystem {
+	case readers.EcosystemNpm, readers.EcosystemCargo, readers.EcosystemGo, readers.EcosystemComposer:
+		av, aErr := parseSemver(a)
+		bv, bErr := parseSemver(b)
+		if aErr != nil || bErr != nil {
+			return 0, false
+		}
+		return compareSemver(av, bv), true
+	case readers.EcosystemMaven, readers.EcosystemNuGet:
+		return compareMaven(a, b), true
+	case readers.EcosystemPyPI:
+		av, aErr := parsePEP440(a)
+		bv, bErr := parsePEP440(b)
+		if aErr != nil || bErr != nil {
+			return 0, false
+		}
+		return comparePEP440(av, bv), true
+	case readers.EcosystemGem:
+		return compareGem(a, b), true
+	}
+	return 0, false
+}
+
+// compareGem compares RubyGems versions: segments compare numerically, and a
+// letter segment marks a pre-release that sorts before the release
+// (1.0.0.pre < 1.0.0).
+func compareGem(a, b string) int {
+	ai, bi := mavenItems(a), mavenItems(b)
+	for i := 0; i < len(ai) || i < len(bi); i++ {
+		x, y := "0", "0"
+		if i < len(ai) {
+			x = ai[i]
+		}
+		if i < len(bi) {
+			y = bi[i]
+		}
+		xNum, yNum := isDigit(rune(x[0])), isDigit(rune(y[0]))
+		var c int
+		switch {
+		case xNum && yNum:
+			c = compareNumeric(x, y)
+		case xNum:
+			c = 1
+		case yNum:
+			c = -1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
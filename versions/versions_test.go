@@ -0,0 +1,94 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/joelcma/dewormer/readers"
+)
+
+func TestParse_PerEcosystem(t *testing.T) {
+	tests := []struct {
+		ecosystem, spec, version string
+		want                     bool
+	}{
+		{readers.EcosystemNpm, "*", "9.9.9", true},
+		{readers.EcosystemGem, "*", "1.0.0", true},
+		{"", "*", "1.0.0", true},
+		{readers.EcosystemNpm, "1.0.0", "1.0.0", true},
+		{readers.EcosystemNpm, "1.0.0", "1.0.1", false},
+		{readers.EcosystemNpm, ">=4.1.0 <4.1.3", "4.1.2", true},
+		{readers.EcosystemGo, ">=v1.2.0 <v1.3.0", "v1.2.5", true},
+		{readers.EcosystemComposer, "dev-main", "dev-main", true},
+		{readers.EcosystemMaven, "[1.0,2.0)", "1.5", true},
+		{readers.EcosystemMaven, "1.0", "1.0", true},
+		{readers.EcosystemNuGet, "[1.0.0,1.2.0]", "1.2.0", true},
+		{readers.EcosystemPyPI, ">=1.0,<1.2", "1.1.post1", true},
+		{readers.EcosystemPyPI, "1.0", "1.0", true},
+		// single versions compare in the ecosystem's ordering
+		{readers.EcosystemComposer, "v5.4.0", "5.4.0", true},
+		{readers.EcosystemGo, "1.2.3", "v1.2.3", true},
+		{readers.EcosystemPyPI, "1.0", "1.0.0", true},
+		{readers.EcosystemMaven, "1.0", "1.0.0", true},
+		{readers.EcosystemNuGet, "13.0.1", "13.0.2", false},
+		// legacy entries evaluated without an ecosystem are exact
+		{"", ">=1.0.0", "1.0.0", false},
+		{"", "1.0", "1.0.0", false},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.ecosystem, tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q, %q): %v", tt.ecosystem, tt.spec, err)
+		}
+		if got := c.Matches(tt.version); got != tt.want {
+			t.Fatalf("Parse(%q, %q).Matches(%q) = %v, want %v", tt.ecosystem, tt.spec, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParse_InvalidRanges(t *testing.T) {
+	for _, tt := range []struct{ ecosystem, spec string }{
+		{readers.EcosystemNpm, ">=abc"},
+		{readers.EcosystemMaven, "[1.0,2.0"},
+		{readers.EcosystemPyPI, ">=1.0,foo"},
+		{readers.EcosystemPyPI, "~=1"},
+	} {
+		if _, err := Parse(tt.ecosystem, tt.spec); err == nil {
+			t.Fatalf("expected error for %q in %s", tt.spec, tt.ecosystem)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		ecosystem, a, b string
+		want            int
+	}{
+		{readers.EcosystemGo, "v1.2.3", "1.2.3", 0},
+		{readers.EcosystemNpm, "1.2.3", "1.10.0", -1},
+		{readers.EcosystemMaven, "1.0-SNAPSHOT", "1.0", -1},
+		{readers.EcosystemPyPI, "1.0", "1.0.0", 0},
+		{readers.EcosystemGem, "1.0.0.pre", "1.0.0", -1},
+		{readers.EcosystemGem, "1.10", "1.9", 1},
+	}
+	for _, tt := range tests {
+		got, ok := Compare(tt.ecosystem, tt.a, tt.b)
+		if !ok || got != tt.want {
+			t.Fatalf("Compare(%q, %q, %q) = %d, %v; want %d", tt.ecosystem, tt.a, tt.b, got, ok, tt.want)
+		}
+	}
+	if _, ok := Compare(readers.EcosystemNpm, "not-a-version", "1.0.0"); ok {
+		t.Fatalf("expected unparseable npm version not to compare")
+	}
+}
+
+func TestInterval(t *testing.T) {
+	iv := Interval{Ecosystem: readers.EcosystemNpm, Lower: "1.0.0", Upper: "1.2.0"}
+	for v, want := range map[string]bool{"0.9.9": false, "1.0.0": true, "1.1.9": true, "1.2.0": false} {
+		if got := iv.Matches(v); got != want {
+			t.Fatalf("Interval.Matches(%q) = %v, want %v", v, got, want)
+		}
+	}
+	if !(Interval{Ecosystem: readers.EcosystemNpm}).Matches("anything") {
+		t.Fatalf("expected unbounded interval to match every version")
+	}
+}